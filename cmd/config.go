@@ -0,0 +1,40 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// envDuration reads a duration like "5s" from the environment, falling back to def when the variable is unset.
+func envDuration(key string, def time.Duration) time.Duration {
+
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+
+	return d
+}
+
+// envInt32 reads an integer from the environment, falling back to def when the variable is unset.
+func envInt32(key string, def int32) int32 {
+
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+
+	n, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+
+	return int32(n)
+}
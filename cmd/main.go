@@ -3,14 +3,16 @@ package main
 import (
 	"GymBot/internal/application"
 	"GymBot/internal/infrastructure/postgres"
+	"GymBot/internal/infrastructure/sqlite"
 	"GymBot/internal/interface/telegram"
-	"database/sql"
+	"context"
 	"log"
 	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
-	_ "github.com/jackc/pgx/v5/stdlib" // pgx driver for database/sql
 	"github.com/joho/godotenv"
 	"gopkg.in/telebot.v3"
 )
@@ -24,25 +26,116 @@ func init() {
 
 func main() {
 	// Read environment variables
+	dbDriver := os.Getenv("DB_DRIVER")
 	dbConnStr := os.Getenv("DB_CONNECTION")
 	botToken := os.Getenv("BOT_TOKEN")
+	autoMigrate := os.Getenv("AUTO_MIGRATE") == "true"
 
-	if dbConnStr == "" || botToken == "" {
-		log.Fatal("Failed to load environment variables. Check BOT_TOKEN and DB_CONNECTION.")
+	// Limits connecting to the database and handling one update, all the queries of the update together
+	opTimeout := envDuration("OPERATION_TIMEOUT", 5*time.Second)
+	pageSize := envInt32("EXERCISE_PAGE_SIZE", application.DefaultPageSize)
+
+	poolConfig := postgres.PoolConfig{
+		MaxConns:          envInt32("DB_MAX_CONNS", 0),
+		MinConns:          envInt32("DB_MIN_CONNS", 0),
+		MaxConnLifetime:   envDuration("DB_MAX_CONN_LIFETIME", 0),
+		MaxConnIdleTime:   envDuration("DB_MAX_CONN_IDLE_TIME", 0),
+		HealthCheckPeriod: envDuration("DB_HEALTH_CHECK_PERIOD", 0),
 	}
 
-	db, err := sql.Open("pgx", dbConnStr)
-	if err != nil {
-		log.Fatalf("Error connecting to the database: %v", err)
+	if pageSize < 1 || pageSize > 20 {
+		log.Fatalf("EXERCISE_PAGE_SIZE must be between 1 and 20, got %d.", pageSize)
+	}
+
+	if dbDriver == "" {
+		dbDriver = driverPostgres
+	}
+
+	migrateMode := len(os.Args) > 1 && os.Args[1] == "migrate"
+
+	if (dbDriver != driverMemory && dbConnStr == "") || (botToken == "" && !migrateMode) {
+		log.Fatal("Failed to load environment variables. Check BOT_TOKEN and DB_CONNECTION.")
 	}
-	defer db.Close()
 
-	if err := db.Ping(); err != nil {
-		log.Fatalf("Failed to connect to the database: %v", err)
+	// Cancelled on SIGINT/SIGTERM so that in-flight database calls stop on shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var st storage
+
+	switch dbDriver {
+	case driverMemory:
+		if migrateMode {
+			log.Fatal("The memory driver has no migrations.")
+		}
+		slog.Warn("Using in-memory storage, data will be lost on restart.")
+		st = newMemoryStorage()
+
+	case driverSQLite:
+		// DB_CONNECTION is the database file path. Pending migrations are applied on start,
+		// the migrate command is there to inspect them and roll them back.
+		if migrateMode {
+			db, err := sqlite.Connect(ctx, dbConnStr)
+			if err != nil {
+				log.Fatalf("Failed to open the database: %v", err)
+			}
+			defer db.Close()
+
+			m, err := newSQLiteMigrator(db)
+			if err != nil {
+				log.Fatalf("Failed to load migrations: %v", err)
+			}
+
+			if err := runMigrate(ctx, m, os.Args[2:]); err != nil {
+				log.Fatalf("Migration failed: %v", err)
+			}
+			return
+		}
+
+		db, err := sqlite.Open(ctx, dbConnStr)
+		if err != nil {
+			log.Fatalf("Failed to open the database: %v", err)
+		}
+		defer db.Close()
+
+		st = newSQLiteStorage(db)
+
+	case driverPostgres:
+		connectCtx, cancel := context.WithTimeout(ctx, opTimeout)
+		defer cancel()
+
+		db, err := postgres.NewPool(connectCtx, dbConnStr, poolConfig)
+		if err != nil {
+			log.Fatalf("Failed to connect to the database: %v", err)
+		}
+		defer db.Close()
+
+		if migrateMode || autoMigrate {
+			m, err := newPostgresMigrator(db)
+			if err != nil {
+				log.Fatalf("Failed to load migrations: %v", err)
+			}
+
+			if migrateMode {
+				if err := runMigrate(ctx, m, os.Args[2:]); err != nil {
+					log.Fatalf("Migration failed: %v", err)
+				}
+				return
+			}
+
+			if err := runMigrate(ctx, m, []string{"up"}); err != nil {
+				log.Fatalf("Auto migration failed: %v", err)
+			}
+		}
+
+		st = newPostgresStorage(db)
+
+	default:
+		log.Fatalf("Unknown DB_DRIVER %q, expected %q, %q or %q.", dbDriver, driverPostgres, driverSQLite, driverMemory)
 	}
 
-	repo := postgres.NewUserRepositoryDb(db)
-	service := application.Initialize(repo) // Initialize service
+	service := application.Initialize(st.users, st.trainings, st.sets, st.exercises, st.stats, st.tx) // Initialize service
+	service.PageSize = int64(pageSize)
 
 	pref := telebot.Settings{
 		Token:  botToken,
@@ -54,10 +147,17 @@ func main() {
 		log.Fatalf("Error initializing bot: %v", err)
 	}
 
-	botHandler := telegram.NewBotHandler(service) // Pass initialized service
+	botHandler := telegram.NewBotHandler(ctx, service, st.dialogs, opTimeout) // Pass initialized service
+	bot.Use(botHandler.WithContext)
 	bot.Handle(telebot.OnText, botHandler.MsgMainHandler)
 	bot.Handle(telebot.OnCallback, botHandler.DataHandler)
 
+	go func() {
+		<-ctx.Done()
+		slog.Info("Shutting down.")
+		bot.Stop()
+	}()
+
 	slog.Info("Bot started.")
 	bot.Start()
 }
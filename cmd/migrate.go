@@ -0,0 +1,131 @@
+package main
+
+import (
+	"GymBot/internal/infrastructure/postgres"
+	"GymBot/internal/infrastructure/sqlite"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type migrationStatus struct {
+	version   int64
+	name      string
+	applied   bool
+	appliedAt time.Time
+}
+
+// migrator wraps postgres.Migrator or sqlite.Migrator, which have the same methods on their own types.
+type migrator struct {
+	up     func(ctx context.Context) (int, error)
+	down   func(ctx context.Context) (int64, string, error)
+	status func(ctx context.Context) ([]migrationStatus, error)
+}
+
+func newPostgresMigrator(db *pgxpool.Pool) (migrator, error) {
+
+	m, err := postgres.NewMigrator(db)
+	if err != nil {
+		return migrator{}, err
+	}
+
+	return migrator{
+		up: func(ctx context.Context) (int, error) {
+			applied, err := m.Up(ctx)
+			return len(applied), err
+		},
+		down: func(ctx context.Context) (int64, string, error) {
+			migration, err := m.Down(ctx)
+			return migration.Version, migration.Name, err
+		},
+		status: func(ctx context.Context) ([]migrationStatus, error) {
+			statuses, err := m.Status(ctx)
+			if err != nil {
+				return nil, err
+			}
+			result := make([]migrationStatus, 0, len(statuses))
+			for _, s := range statuses {
+				result = append(result, migrationStatus{s.Version, s.Name, s.Applied, s.AppliedAt})
+			}
+			return result, nil
+		},
+	}, nil
+}
+
+func newSQLiteMigrator(db *sql.DB) (migrator, error) {
+
+	m, err := sqlite.NewMigrator(db)
+	if err != nil {
+		return migrator{}, err
+	}
+
+	return migrator{
+		up: func(ctx context.Context) (int, error) {
+			applied, err := m.Up(ctx)
+			return len(applied), err
+		},
+		down: func(ctx context.Context) (int64, string, error) {
+			migration, err := m.Down(ctx)
+			return migration.Version, migration.Name, err
+		},
+		status: func(ctx context.Context) ([]migrationStatus, error) {
+			statuses, err := m.Status(ctx)
+			if err != nil {
+				return nil, err
+			}
+			result := make([]migrationStatus, 0, len(statuses))
+			for _, s := range statuses {
+				result = append(result, migrationStatus{s.Version, s.Name, s.Applied, s.AppliedAt})
+			}
+			return result, nil
+		},
+	}, nil
+}
+
+// runMigrate handles "migrate up|down|status".
+func runMigrate(ctx context.Context, m migrator, args []string) error {
+
+	if len(args) != 1 {
+		return fmt.Errorf("usage: migrate up|down|status")
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := m.up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migration(s).\n", applied)
+	case "down":
+		version, name, err := m.down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rolled back %04d_%s.\n", version, name)
+	case "status":
+		statuses, err := m.status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.applied {
+				appliedAt = s.appliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.version, s.name, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or status", args[0])
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"GymBot/internal/domain/repository"
+	"GymBot/internal/infrastructure/memory"
+	"GymBot/internal/infrastructure/postgres"
+	"GymBot/internal/infrastructure/sqlite"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	driverPostgres = "postgres"
+	driverSQLite   = "sqlite"
+	driverMemory   = "memory"
+)
+
+// storage is the set of repositories of one database backend, selected by DB_DRIVER.
+type storage struct {
+	users     repository.UserRepository
+	trainings repository.TrainingRepository
+	sets      repository.SetRepository
+	exercises repository.ExerciseRepository
+	stats     repository.StatsRepository
+	dialogs   repository.DialogRepository
+	tx        repository.UnitOfWork
+}
+
+func newPostgresStorage(db *pgxpool.Pool) storage {
+	return storage{
+		users:     postgres.NewUserRepositoryDb(db),
+		trainings: postgres.NewTrainingRepositoryDb(db),
+		sets:      postgres.NewSetRepositoryDb(db),
+		exercises: postgres.NewExerciseRepositoryDb(db),
+		stats:     postgres.NewStatsRepositoryDb(db),
+		dialogs:   postgres.NewDialogRepositoryDb(db),
+		tx:        postgres.NewUnitOfWorkDb(db),
+	}
+}
+
+func newSQLiteStorage(db *sql.DB) storage {
+	return storage{
+		users:     sqlite.NewUserRepositoryDb(db),
+		trainings: sqlite.NewTrainingRepositoryDb(db),
+		sets:      sqlite.NewSetRepositoryDb(db),
+		exercises: sqlite.NewExerciseRepositoryDb(db),
+		stats:     sqlite.NewStatsRepositoryDb(db),
+		dialogs:   sqlite.NewDialogRepositoryDb(db),
+		tx:        sqlite.NewUnitOfWorkDb(db),
+	}
+}
+
+// newMemoryStorage keeps everything in process memory, data is lost on restart.
+func newMemoryStorage() storage {
+
+	s := memory.NewStorage()
+
+	return storage{
+		users:     s,
+		trainings: s,
+		sets:      s,
+		exercises: s,
+		stats:     s,
+		dialogs:   s,
+		tx:        s,
+	}
+}
@@ -0,0 +1,272 @@
+package application
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+)
+
+const (
+	// SearchLimit is the most matches SearchExercises returns, they have to fit on one keyboard.
+	SearchLimit = 10
+	// PinnedLimit is the most exercises pinned above the picker.
+	PinnedLimit = 6
+)
+
+// Catalog returns the user's exercises: the ones shown in the picker and the archived ones.
+func (s *Service) Catalog(ctx context.Context, id int64) ([]domain.Exercise, []domain.Exercise, error) {
+
+	active, err := s.Exercises.GetExercises(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	archived, err := s.Exercises.GetArchivedExercises(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return active, archived, nil
+}
+
+// Exercise returns the user's exercise by id. It returns ErrUnknownExercise if the user has no such exercise,
+// so an id taken from callback data can't reach another user's exercise.
+func (s *Service) Exercise(ctx context.Context, id, exerciseID int64) (domain.Exercise, error) {
+	return s.Exercises.GetExercise(ctx, id, exerciseID)
+}
+
+// SearchExercises returns the user's active exercises that match the query, see domain.MatchExerciseName.
+// Exact matches come first, then the ones with fewer typos.
+func (s *Service) SearchExercises(ctx context.Context, id int64, query string) ([]domain.Exercise, error) {
+
+	matches, err := s.searchExercises(ctx, id, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []domain.Exercise
+	for _, m := range matches[:min(len(matches), SearchLimit)] {
+		found = append(found, m.exercise)
+	}
+
+	return found, nil
+}
+
+// MatchExercise returns the active exercise the query names: the one with the same domain.ExerciseNameKey,
+// or else the matches of SearchExercises with the fewest typos. More than one exercise means the query
+// is ambiguous, none means there is no such exercise.
+func (s *Service) MatchExercise(ctx context.Context, id int64, query string) ([]domain.Exercise, error) {
+
+	matches, err := s.searchExercises(ctx, id, query)
+	if err != nil {
+		return nil, err
+	}
+
+	key := domain.ExerciseNameKey(strings.Join(strings.Fields(query), " "))
+
+	var best []domain.Exercise
+	for _, m := range matches {
+		if domain.ExerciseNameKey(m.exercise.Name) == key {
+			return []domain.Exercise{m.exercise}, nil
+		}
+		if m.typos == matches[0].typos {
+			best = append(best, m.exercise)
+		}
+	}
+
+	return best, nil
+}
+
+type exerciseMatch struct {
+	exercise domain.Exercise
+	typos    int
+}
+
+// searchExercises returns the matching active exercises ordered by the number of typos.
+func (s *Service) searchExercises(ctx context.Context, id int64, query string) ([]exerciseMatch, error) {
+
+	exercises, err := s.Exercises.GetExercises(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []exerciseMatch
+	for _, e := range exercises {
+		if typos, ok := domain.MatchExerciseName(query, e.Name); ok {
+			matches = append(matches, exerciseMatch{e, typos})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].typos < matches[j].typos
+	})
+
+	return matches, nil
+}
+
+// RenameExercise renames the exercise together with all of its recorded sets and returns the new name.
+// Renaming to a name that matches another exercise is rejected, MergeExercise does that.
+func (s *Service) RenameExercise(ctx context.Context, id, exerciseID int64, newName string) (string, error) {
+
+	name, err := domain.NormalizeExerciseName(newName)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.Tx.Do(ctx, func(ctx context.Context) error {
+
+		exercise, err := s.Exercises.GetExercise(ctx, id, exerciseID)
+		if err != nil {
+			return err
+		}
+
+		if name == exercise.Name {
+			return nil
+		}
+
+		// Changing only the case or the look-alike letters of the name is fine
+		existing, found, err := s.findExercise(ctx, id, name)
+		if err != nil {
+			return err
+		}
+
+		if found && existing.Exercise_id != exercise.Exercise_id {
+			return domain.ErrExerciseExists
+		}
+
+		if err := s.Exercises.RenameExercise(ctx, id, exercise.Exercise_id, name); err != nil {
+			return err
+		}
+
+		return s.Sets.RenameExerciseSets(ctx, id, exercise.Name, name)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// ArchiveExercise hides the exercise from the picker. Its sets stay in the statistics.
+func (s *Service) ArchiveExercise(ctx context.Context, id, exerciseID int64) error {
+	return s.setArchived(ctx, id, exerciseID, true)
+}
+
+func (s *Service) RestoreExercise(ctx context.Context, id, exerciseID int64) error {
+	return s.setArchived(ctx, id, exerciseID, false)
+}
+
+// FavoriteExercise pins the exercise at the top of the picker.
+func (s *Service) FavoriteExercise(ctx context.Context, id, exerciseID int64) error {
+	return s.setFavorite(ctx, id, exerciseID, true)
+}
+
+func (s *Service) UnfavoriteExercise(ctx context.Context, id, exerciseID int64) error {
+	return s.setFavorite(ctx, id, exerciseID, false)
+}
+
+// DeleteExercise removes an exercise that has no recorded sets, otherwise it returns ErrExerciseInUse.
+// An exercise chosen for the open set is not removed either, it returns ErrExerciseChosen.
+func (s *Service) DeleteExercise(ctx context.Context, id, exerciseID int64) error {
+
+	return s.Tx.Do(ctx, func(ctx context.Context) error {
+
+		exercise, err := s.Exercises.GetExercise(ctx, id, exerciseID)
+		if err != nil {
+			return err
+		}
+
+		sets, err := s.Stats.GetTotalSetsPerExercise(ctx, id, exercise.Name)
+		if err != nil {
+			return err
+		}
+
+		if sets > 0 {
+			return domain.ErrExerciseInUse
+		}
+
+		set, err := s.Sets.LockOpenSet(ctx, id)
+		switch {
+		case err == nil:
+			if set.Exercise == exercise.Name {
+				return domain.ErrExerciseChosen
+			}
+		case !errors.Is(err, domain.ErrNoOpenSet):
+			return err
+		}
+
+		return s.Exercises.DeleteExercise(ctx, id, exercise.Exercise_id)
+	})
+}
+
+// MergeExercise moves all sets of the exercise to another one and removes the merged exercise.
+func (s *Service) MergeExercise(ctx context.Context, id, exerciseID, intoID int64) error {
+
+	if exerciseID == intoID {
+		return domain.ErrMergeIntoItself
+	}
+
+	return s.Tx.Do(ctx, func(ctx context.Context) error {
+
+		exercise, err := s.Exercises.GetExercise(ctx, id, exerciseID)
+		if err != nil {
+			return err
+		}
+
+		into, err := s.Exercises.GetExercise(ctx, id, intoID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.Sets.RenameExerciseSets(ctx, id, exercise.Name, into.Name); err != nil {
+			return err
+		}
+
+		return s.Exercises.DeleteExercise(ctx, id, exercise.Exercise_id)
+	})
+}
+
+// findExercise returns the user's exercise that has the same domain.ExerciseNameKey as the name,
+// archived ones included, and whether there is one.
+func (s *Service) findExercise(ctx context.Context, id int64, name string) (domain.Exercise, bool, error) {
+
+	active, archived, err := s.Catalog(ctx, id)
+	if err != nil {
+		return domain.Exercise{}, false, err
+	}
+
+	key := domain.ExerciseNameKey(name)
+	for _, e := range append(active, archived...) {
+		if domain.ExerciseNameKey(e.Name) == key {
+			return e, true, nil
+		}
+	}
+
+	return domain.Exercise{}, false, nil
+}
+
+func (s *Service) setArchived(ctx context.Context, id, exerciseID int64, archived bool) error {
+
+	return s.Tx.Do(ctx, func(ctx context.Context) error {
+
+		if _, err := s.Exercises.GetExercise(ctx, id, exerciseID); err != nil {
+			return err
+		}
+
+		return s.Exercises.SetExerciseArchived(ctx, id, exerciseID, archived)
+	})
+}
+
+func (s *Service) setFavorite(ctx context.Context, id, exerciseID int64, favorite bool) error {
+
+	return s.Tx.Do(ctx, func(ctx context.Context) error {
+
+		if _, err := s.Exercises.GetExercise(ctx, id, exerciseID); err != nil {
+			return err
+		}
+
+		return s.Exercises.SetExerciseFavorite(ctx, id, exerciseID, favorite)
+	})
+}
@@ -0,0 +1,45 @@
+package application
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeleteChosenExercise(t *testing.T) {
+
+	ctx := context.Background()
+	s, _ := newService(t)
+
+	if err := s.StartTraining(ctx, testUser, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.AddExercise(ctx, testUser, "Присед"); err != nil {
+		t.Fatal(err)
+	}
+
+	active, _, err := s.Catalog(ctx, testUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exerciseID := active[0].Exercise_id
+
+	if _, err := s.ChooseExercise(ctx, testUser, exerciseID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.DeleteExercise(ctx, testUser, exerciseID); !errors.Is(err, domain.ErrExerciseChosen) {
+		t.Fatalf("DeleteExercise of the chosen exercise = %v, want %v", err, domain.ErrExerciseChosen)
+	}
+
+	if _, err := s.CancelSet(ctx, testUser); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.DeleteExercise(ctx, testUser, exerciseID); err != nil {
+		t.Fatalf("DeleteExercise after the choice is cancelled = %v", err)
+	}
+}
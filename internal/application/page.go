@@ -0,0 +1,36 @@
+package application
+
+import "context"
+
+// Page is one page of a list. Page numbers start at 1.
+type Page[T any] struct {
+	Items []T
+	Page  int64
+	Pages int64
+}
+
+// paginate loads the requested page of size items. A page past the end, e.g. after items
+// were deleted, is clamped to the last one. An empty list has one empty page.
+func paginate[T any](ctx context.Context, page, size int64,
+	count func(ctx context.Context) (int64, error),
+	load func(ctx context.Context, offset, limit int64) ([]T, error)) (Page[T], error) {
+
+	total, err := count(ctx)
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	pages := max((total+size-1)/size, 1)
+	page = max(1, min(page, pages))
+
+	items, err := load(ctx, (page-1)*size, size)
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	return Page[T]{
+		Items: items,
+		Page:  page,
+		Pages: pages,
+	}, nil
+}
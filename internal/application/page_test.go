@@ -0,0 +1,50 @@
+package application
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		total     int
+		page      int64
+		wantPage  int64
+		wantPages int64
+		want      []int
+	}{
+		{"empty list", 0, 1, 1, 1, nil},
+		{"empty list, page past the end", 0, 3, 1, 1, nil},
+		{"first page", 7, 1, 1, 3, []int{0, 1, 2}},
+		{"last page", 7, 3, 3, 3, []int{6}},
+		{"page past the end", 7, 9, 3, 3, []int{6}},
+		{"page before the first", 7, 0, 1, 3, []int{0, 1, 2}},
+		{"full last page", 6, 2, 2, 2, []int{3, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		items := make([]int, tt.total)
+		for i := range items {
+			items[i] = i
+		}
+
+		page, err := paginate(context.Background(), tt.page, 3,
+			func(ctx context.Context) (int64, error) {
+				return int64(len(items)), nil
+			},
+			func(ctx context.Context, offset, limit int64) ([]int, error) {
+				return items[offset:min(offset+limit, int64(len(items)))], nil
+			})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if page.Page != tt.wantPage || page.Pages != tt.wantPages || !slices.Equal(page.Items, tt.want) {
+			t.Errorf("%s: paginate(%d) = page %d of %d %v, want page %d of %d %v",
+				tt.name, tt.page, page.Page, page.Pages, page.Items, tt.wantPage, tt.wantPages, tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package application
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"context"
+	"time"
+)
+
+// MaxTrainingDuration is the longest training AddPastTraining accepts.
+const MaxTrainingDuration = 8 * time.Hour
+
+// PastSets is an entry of the sets of a past training: Count identical sets of the exercise.
+type PastSets struct {
+	Exercise_id int64
+	Weight      float64
+	Reps        int
+	Count       int
+}
+
+// CheckPastTraining returns the training that started at start and lasted for duration, without storing
+// it. It must be over by now and must not overlap the user's other trainings, the active one included.
+func (s *Service) CheckPastTraining(ctx context.Context, id int64, start time.Time, duration time.Duration, now time.Time) (domain.Training, error) {
+
+	if duration <= 0 || duration > MaxTrainingDuration {
+		return domain.Training{}, domain.ErrInvalidDuration
+	}
+
+	training := domain.Training{
+		User_id: id,
+		Start:   start,
+		End:     start.Add(duration),
+	}
+
+	if training.End.After(now) {
+		return domain.Training{}, domain.ErrTrainingInFuture
+	}
+
+	trainings, err := s.Trainings.GetTrainings(ctx, id)
+	if err != nil {
+		return domain.Training{}, err
+	}
+
+	if overlaps(training, trainings, now) {
+		return domain.Training{}, domain.ErrTrainingOverlaps
+	}
+
+	return training, nil
+}
+
+// CheckPastSets validates an entry of the sets of a past training and returns its exercise, which
+// must be one of the user's active exercises. Nothing is stored until AddPastTraining.
+func (s *Service) CheckPastSets(ctx context.Context, id int64, sets PastSets) (domain.Exercise, error) {
+
+	if err := validateSetEntry(sets.Weight, sets.Reps, sets.Count); err != nil {
+		return domain.Exercise{}, err
+	}
+
+	return s.activeExercise(ctx, id, sets.Exercise_id)
+}
+
+// AddPastTraining stores a training that was not tracked live together with its sets in one
+// transaction, so a training is never left without them. The training and the sets are checked
+// again, the user's trainings and exercises may have changed while they were entered.
+func (s *Service) AddPastTraining(ctx context.Context, id int64, start time.Time, duration time.Duration, sets []PastSets, now time.Time) (domain.Training, error) {
+
+	if len(sets) == 0 {
+		return domain.Training{}, domain.ErrEmptyTraining
+	}
+
+	var training domain.Training
+	err := s.Tx.Do(ctx, func(ctx context.Context) error {
+
+		var err error
+		training, err = s.CheckPastTraining(ctx, id, start, duration, now)
+		if err != nil {
+			return err
+		}
+
+		training.Training_id, err = s.Trainings.AddTraining(ctx, training)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range sets {
+			exercise, err := s.CheckPastSets(ctx, id, entry)
+			if err != nil {
+				return err
+			}
+
+			for i := 0; i < entry.Count; i++ {
+				_, err := s.Sets.AddSet(ctx, domain.Set{
+					User_id:     id,
+					Training_id: training.Training_id,
+					Exercise:    exercise.Name,
+					Weight:      entry.Weight,
+					Reps:        entry.Reps,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.Training{}, err
+	}
+
+	return training, nil
+}
+
+// overlaps reports whether the training shares any time with one of the others. The active
+// training, the one without an end, lasts until now.
+func overlaps(training domain.Training, others []domain.Training, now time.Time) bool {
+
+	for _, t := range others {
+		end := t.End
+		if end.IsZero() {
+			end = now
+		}
+		if training.Start.Before(end) && t.Start.Before(training.End) {
+			return true
+		}
+	}
+
+	return false
+}
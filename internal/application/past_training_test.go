@@ -0,0 +1,103 @@
+package application
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOverlaps(t *testing.T) {
+
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+	now := at(20)
+
+	others := []domain.Training{
+		{Start: at(10), End: at(12)},
+		{Start: at(18)}, // active
+	}
+
+	tests := []struct {
+		name       string
+		start, end int
+		want       bool
+	}{
+		{"before", 7, 9, false},
+		{"ends at the start", 8, 10, false},
+		{"starts at the end", 12, 13, false},
+		{"between", 13, 17, false},
+		{"overlaps the start", 9, 11, true},
+		{"overlaps the end", 11, 13, true},
+		{"inside", 10, 11, true},
+		{"around", 9, 13, true},
+		{"overlaps the active one", 17, 19, true},
+		{"ends when the active one starts", 16, 18, false},
+	}
+
+	for _, tt := range tests {
+		training := domain.Training{Start: at(tt.start), End: at(tt.end)}
+		if got := overlaps(training, others, now); got != tt.want {
+			t.Errorf("%s: overlaps(%d–%d) = %t, want %t", tt.name, tt.start, tt.end, got, tt.want)
+		}
+	}
+}
+
+func TestAddPastTraining(t *testing.T) {
+
+	ctx := context.Background()
+	s, st := newService(t)
+
+	if _, err := s.AddExercise(ctx, testUser, "Присед"); err != nil {
+		t.Fatal(err)
+	}
+
+	active, _, err := s.Catalog(ctx, testUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	squat := active[0].Exercise_id
+
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+	start := now.Add(-24 * time.Hour)
+
+	trainings := func() []domain.Training {
+		all, err := st.GetTrainings(ctx, testUser)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return all
+	}
+
+	if _, err := s.AddPastTraining(ctx, testUser, start, time.Hour, nil, now); !errors.Is(err, domain.ErrEmptyTraining) {
+		t.Errorf("AddPastTraining without sets = %v, want %v", err, domain.ErrEmptyTraining)
+	}
+
+	unknown := []PastSets{{Exercise_id: squat, Weight: 100, Reps: 5, Count: 3}, {Exercise_id: squat + 1, Weight: 50, Reps: 5, Count: 1}}
+	if _, err := s.AddPastTraining(ctx, testUser, start, time.Hour, unknown, now); !errors.Is(err, domain.ErrUnknownExercise) {
+		t.Errorf("AddPastTraining with an unknown exercise = %v, want %v", err, domain.ErrUnknownExercise)
+	}
+
+	if got := trainings(); len(got) != 0 {
+		t.Fatalf("trainings after the failed adds = %v, want none", got)
+	}
+
+	training, err := s.AddPastTraining(ctx, testUser, start, time.Hour, unknown[:1], now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sets, err := st.GetTrainingSets(ctx, testUser, training.Training_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(trainings()) != 1 || len(sets) != 3 {
+		t.Errorf("after AddPastTraining: %d trainings, %d sets, want 1 training, 3 sets", len(trainings()), len(sets))
+	}
+
+	if _, err := s.AddPastTraining(ctx, testUser, start.Add(30*time.Minute), time.Hour, unknown[:1], now); !errors.Is(err, domain.ErrTrainingOverlaps) {
+		t.Errorf("AddPastTraining overlapping the added one = %v, want %v", err, domain.ErrTrainingOverlaps)
+	}
+}
@@ -2,14 +2,35 @@ package application
 
 import "GymBot/internal/domain/repository"
 
+// DefaultPageSize is the number of exercises on a page of the picker.
+const DefaultPageSize = 5
+
 type Service struct {
-	Repo repository.UserRepository
-	//Analitycs repository.AnalitycsRepository
+	Users     repository.UserRepository
+	Trainings repository.TrainingRepository
+	Sets      repository.SetRepository
+	Exercises repository.ExerciseRepository
+	Stats     repository.StatsRepository
+	Tx        repository.UnitOfWork
+
+	PageSize int64
 }
 
-func Initialize(repo repository.UserRepository) *Service {
+func Initialize(
+	users repository.UserRepository,
+	trainings repository.TrainingRepository,
+	sets repository.SetRepository,
+	exercises repository.ExerciseRepository,
+	stats repository.StatsRepository,
+	tx repository.UnitOfWork,
+) *Service {
 	return &Service{
-		Repo: repo,
-		//	Analitycs: analitycs,
+		Users:     users,
+		Trainings: trainings,
+		Sets:      sets,
+		Exercises: exercises,
+		Stats:     stats,
+		Tx:        tx,
+		PageSize:  DefaultPageSize,
 	}
 }
@@ -0,0 +1,22 @@
+package application
+
+import (
+	"GymBot/internal/infrastructure/memory"
+	"context"
+	"testing"
+)
+
+const testUser = 1
+
+// newService returns a service over an empty in-memory storage with testUser registered.
+func newService(t *testing.T) (*Service, *memory.Storage) {
+
+	st := memory.NewStorage()
+	s := Initialize(st, st, st, st, st, st)
+
+	if err := st.RegisterUser(context.Background(), testUser); err != nil {
+		t.Fatal(err)
+	}
+
+	return s, st
+}
@@ -0,0 +1,107 @@
+package application
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"context"
+)
+
+// TrainingSets returns the recorded sets of the active training ordered by set id.
+func (s *Service) TrainingSets(ctx context.Context, id int64) ([]domain.Set, error) {
+
+	training, err := s.Trainings.GetActiveTraining(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Sets.GetTrainingSets(ctx, id, training.Training_id)
+}
+
+// Set returns the user's recorded set by id. It returns ErrUnknownSet if the user has no such set,
+// so an id taken from callback data can't reach another user's set.
+func (s *Service) Set(ctx context.Context, id, setID int64) (domain.Set, error) {
+	return s.Sets.GetSet(ctx, id, setID)
+}
+
+// EditSetWeight changes the weight of the recorded set and returns the changed set.
+func (s *Service) EditSetWeight(ctx context.Context, id, setID int64, weight float64) (domain.Set, error) {
+
+	if err := validateWeight(weight); err != nil {
+		return domain.Set{}, err
+	}
+
+	return s.editSet(ctx, id, setID, func(ctx context.Context, set *domain.Set) error {
+		set.Weight = weight
+		return nil
+	})
+}
+
+// EditSetReps changes the reps of the recorded set and returns the changed set.
+func (s *Service) EditSetReps(ctx context.Context, id, setID int64, reps int) (domain.Set, error) {
+
+	if err := validateReps(reps); err != nil {
+		return domain.Set{}, err
+	}
+
+	return s.editSet(ctx, id, setID, func(ctx context.Context, set *domain.Set) error {
+		set.Reps = reps
+		return nil
+	})
+}
+
+// EditSetExercise moves the recorded set to another active exercise and returns the changed set.
+func (s *Service) EditSetExercise(ctx context.Context, id, setID, exerciseID int64) (domain.Set, error) {
+
+	return s.editSet(ctx, id, setID, func(ctx context.Context, set *domain.Set) error {
+
+		exercise, err := s.Exercises.GetExercise(ctx, id, exerciseID)
+		if err != nil {
+			return err
+		}
+
+		if exercise.Archived {
+			return domain.ErrUnknownExercise
+		}
+
+		set.Exercise = exercise.Name
+
+		return nil
+	})
+}
+
+// DeleteSet removes the user's recorded set.
+func (s *Service) DeleteSet(ctx context.Context, id, setID int64) error {
+
+	return s.Tx.Do(ctx, func(ctx context.Context) error {
+
+		if _, err := s.Sets.GetSet(ctx, id, setID); err != nil {
+			return err
+		}
+
+		return s.Sets.DeleteSet(ctx, setID)
+	})
+}
+
+// editSet loads the user's recorded set, applies the change and saves it in one transaction.
+func (s *Service) editSet(ctx context.Context, id, setID int64, change func(ctx context.Context, set *domain.Set) error) (domain.Set, error) {
+
+	var set domain.Set
+	err := s.Tx.Do(ctx, func(ctx context.Context) error {
+
+		var err error
+		set, err = s.Sets.GetSet(ctx, id, setID)
+		if err != nil {
+			return err
+		}
+
+		if err := change(ctx, &set); err != nil {
+			return err
+		}
+
+		return s.Sets.UpdateSet(ctx, set)
+	})
+	if err != nil {
+		return domain.Set{}, err
+	}
+
+	return set, nil
+}
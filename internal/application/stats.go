@@ -0,0 +1,159 @@
+package application
+
+import (
+	"GymBot/internal/pkg"
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func (s *Service) GenerateExelStats(ctx context.Context, id int64, userName string) (string, error) {
+
+	stats := excelize.NewFile()
+
+	defer func() {
+		if err := stats.Close(); err != nil {
+			slog.Info("Stats file close err:", slog.Any("err", err))
+		}
+
+	}()
+
+	sheetName := fmt.Sprintf("Статистика %s", userName)
+
+	_, err := stats.NewSheet(sheetName)
+	if err != nil {
+		slog.Error("NewSheet Error:", slog.Any("err", err))
+		return "", err
+	}
+
+	trainings, err := s.Trainings.GetTrainings(ctx, id)
+	if err != nil {
+		slog.Error("GetTrainings Error:", slog.Any("err", err))
+
+	}
+
+	var date []time.Time
+	for i := 0; i < len(trainings); i++ {
+		date = append(date, trainings[i].Start)
+	}
+
+	start, end := trainingsSort(date)
+
+	earliestTraining := internal.FormatDate(start)
+	latestTraining := internal.FormatDate(end)
+
+	averageTrainingLenght, err := s.Stats.GetAverageTrainingsLenght(ctx, id)
+	if err != nil {
+		slog.Warn("GetAverageTrainingsLenght Error:", slog.Any("err", err))
+	}
+
+	averageExercisesPerTraining, err := s.Stats.GetAverageExercisesPerTraining(ctx, id)
+	if err != nil {
+		slog.Warn("GetAverageExercisesPerTraining Error:", slog.Any("err", err))
+	}
+
+	averageSetsPerTraining, err := s.Stats.GetAverageSetsPerTraining(ctx, id)
+	if err != nil {
+		slog.Warn("GetAverageSetsPerTraining Error:", slog.Any("err", err))
+	}
+
+	MostPopularExercise, err := s.Stats.GetMostPopularExercise(ctx, id)
+	if err != nil {
+		slog.Warn("GetMostPopularExercise Error:", slog.Any("err", err))
+	}
+
+	LeastPopularExercise, err := s.Stats.GetLeastPopularExercise(ctx, id)
+	if err != nil {
+		slog.Warn("GetLeastPopularExercise Error:", slog.Any("err", err))
+	}
+
+	stats.SetColWidth(sheetName, "A", "A", 50)
+	stats.SetColWidth(sheetName, "B", "B", 35)
+	stats.SetColWidth(sheetName, "C", "C", 42)
+	stats.SetColWidth(sheetName, "D", "D", 33)
+	stats.SetColWidth(sheetName, "E", "E", 15)
+
+	stats.SetCellValue(sheetName, "A3", fmt.Sprintf("Количество тренрировок за с %s по %s", earliestTraining, latestTraining))
+	stats.SetCellValue(sheetName, "B3", len(trainings))
+	stats.SetCellValue(sheetName, "A5", "СРЕДНЯЯ ПРОДОЛЖИТЕЛЬНОСТЬ ТРЕНИРОВКИ")
+	stats.SetCellValue(sheetName, "B5", averageTrainingLenght)
+	stats.SetCellValue(sheetName, "A7", "МАКСИМАЛЬНЫЙ СТРИК")
+	stats.SetCellValue(sheetName, "B7", "TODO")
+	stats.SetCellValue(sheetName, "A9", "СРЕДНЕЕ КОЛИЧЕСТВО УПРАЖНЕНИЙ ЗА ТРЕНИРОВКУ")
+	stats.SetCellValue(sheetName, "B9", averageExercisesPerTraining)
+	stats.SetCellValue(sheetName, "A11", "СРЕДНЕЕ КОЛИЧЕСТВО СЭТОВ ЗА ТРЕНИРОВКУ")
+	stats.SetCellValue(sheetName, "B11", averageSetsPerTraining)
+	stats.SetCellValue(sheetName, "A13", "САМОЕ ПОПУЛЯРНОЕ УПРАЖНЕНИЕ")
+	stats.SetCellValue(sheetName, "B13", MostPopularExercise)
+	stats.SetCellValue(sheetName, "A15", "САМОЕ НЕПОПУЛЯРНОЕ УПРАЖНЕНИЕ ")
+	stats.SetCellValue(sheetName, "B15", LeastPopularExercise)
+	stats.SetCellValue(sheetName, "A17", "СТАТИСТИКА ПО КАЖДОМУ УПРАЖНЕНИЮ")
+	stats.SetCellValue(sheetName, "A18", "НАЗВАНИЕ УПРАЖНЕНИЯ")
+	stats.SetCellValue(sheetName, "B18", "СЭТОВ БЫЛО СДЕЛАНО ЗА ВСЕ ВРЕМЯ")
+	stats.SetCellValue(sheetName, "C18", "СРЕДНЕЕ КОЛИЧЕСТВО СЭТОВ ЗА ТРЕНИРОВКУ")
+	stats.SetCellValue(sheetName, "D18", "СРЕДНЕЕ КОЛИЧЕСТВО ПОВТОРЕНИЙ")
+	stats.SetCellValue(sheetName, "E18", "СРЕДНИЙ ВЕС")
+
+	// Archived exercises are only hidden from the picker, their sets still count
+	active, archived, err := s.Catalog(ctx, id)
+	if err != nil {
+		slog.Warn("Catalog Error:", slog.Any("err", err))
+	}
+	exercices := append(active, archived...)
+
+	for i := 0; i < len(exercices); i++ {
+
+		row := 19 + i
+
+		totalSets, err := s.Stats.GetTotalSetsPerExercise(ctx, id, exercices[i].Name)
+		if err != nil {
+			slog.Warn("GetTotalSetsPerExercise error in statsBuilder:", slog.Any("err", err))
+		}
+
+		avgSets, err := s.Stats.GetAverageSetsPerExerise(ctx, id, exercices[i].Name)
+		if err != nil {
+			slog.Warn("getAvgSetsPerExercise error in statsBuilder", slog.Any("err", err))
+		}
+
+		avgReps, err := s.Stats.GetAverageReps(ctx, id, exercices[i].Name)
+		if err != nil {
+			slog.Warn("GetAverageReps Error:", slog.Any("err", err))
+		}
+
+		avgWeight, err := s.Stats.GetAverageWeight(ctx, id, exercices[i].Name)
+		if err != nil {
+			slog.Warn("GetAverageWeight Error:", slog.Any("err", err))
+		}
+
+		stats.SetCellValue(sheetName, fmt.Sprintf("A%d", row), exercices[i].Name)
+		stats.SetCellValue(sheetName, fmt.Sprintf("B%d", row), totalSets)
+		stats.SetCellValue(sheetName, fmt.Sprintf("C%d", row), avgSets)
+		stats.SetCellValue(sheetName, fmt.Sprintf("D%d", row), avgReps)
+		stats.SetCellValue(sheetName, fmt.Sprintf("E%d", row), fmt.Sprintf("%.2f", avgWeight))
+	}
+
+	filePath := fmt.Sprintf("%s_stats.xlsx", userName)
+
+	if err := stats.SaveAs(filePath); err != nil {
+		slog.Error("SaveAs Error:", slog.Any("err", err))
+		return "", err
+	}
+
+	return filePath, err
+
+}
+
+func trainingsSort(trainingsStart []time.Time) (first time.Time, last time.Time) {
+
+	sort.Slice(trainingsStart, func(i, j int) bool {
+		return trainingsStart[i].Before(trainingsStart[j])
+
+	})
+
+	return trainingsStart[0], trainingsStart[len(trainingsStart)-1]
+
+}
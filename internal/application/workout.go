@@ -0,0 +1,483 @@
+package application
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"context"
+	"errors"
+	"time"
+)
+
+// EnsureUser registers the user on the first visit and reports whether the user is new.
+func (s *Service) EnsureUser(ctx context.Context, id int64) (bool, error) {
+
+	exists, err := s.Users.UserCheck(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	if exists {
+		return false, nil
+	}
+
+	if err := s.Users.RegisterUser(ctx, id); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// StartTraining starts a new training. A user can have only one active training at a time.
+func (s *Service) StartTraining(ctx context.Context, id int64, startTime time.Time) error {
+
+	isActive, err := s.Trainings.IsTrainingActive(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if isActive {
+		return domain.ErrTrainingAlreadyActive
+	}
+
+	return s.Trainings.StartTrainig(ctx, id, startTime)
+}
+
+// EndTraining ends the active training. A set that is chosen or running but has no weight
+// and reps yet is dropped, so the training only keeps fully recorded sets.
+func (s *Service) EndTraining(ctx context.Context, id int64, endTime time.Time) error {
+
+	return s.Tx.Do(ctx, func(ctx context.Context) error {
+
+		if err := s.requireActiveTraining(ctx, id); err != nil {
+			return err
+		}
+
+		set, err := s.Sets.LockOpenSet(ctx, id)
+		switch {
+		case err == nil:
+			if err := s.Sets.DeleteSet(ctx, set.Set_id); err != nil {
+				return err
+			}
+		case !errors.Is(err, domain.ErrNoOpenSet):
+			return err
+		}
+
+		return s.Trainings.EndTraining(ctx, id, endTime)
+	})
+}
+
+func (s *Service) IsTrainingActive(ctx context.Context, id int64) (bool, error) {
+	return s.Trainings.IsTrainingActive(ctx, id)
+}
+
+// ChooseExercise opens a new set of the exercise and returns the exercise. The exercise must be
+// one of the user's active exercises. An exercise chosen earlier is switched as long as its set
+// is not started, otherwise the running set has to be finished or cancelled first.
+func (s *Service) ChooseExercise(ctx context.Context, id, exerciseID int64) (domain.Exercise, error) {
+
+	var exercise domain.Exercise
+	err := s.Tx.Do(ctx, func(ctx context.Context) error {
+
+		if err := s.requireActiveTraining(ctx, id); err != nil {
+			return err
+		}
+
+		var err error
+		exercise, err = s.Exercises.GetExercise(ctx, id, exerciseID)
+		if err != nil {
+			return err
+		}
+
+		if exercise.Archived {
+			return domain.ErrUnknownExercise
+		}
+
+		set, err := s.Sets.LockOpenSet(ctx, id)
+		switch {
+		case err == nil:
+			if !set.Start.IsZero() {
+				return domain.ErrSetAlreadyOpen
+			}
+			if err := s.Sets.DeleteSet(ctx, set.Set_id); err != nil {
+				return err
+			}
+		case !errors.Is(err, domain.ErrNoOpenSet):
+			return err
+		}
+
+		return s.Sets.SetExercise(ctx, id, exercise.Name)
+	})
+	if err != nil {
+		return domain.Exercise{}, err
+	}
+
+	return exercise, nil
+}
+
+// CancelSet drops the chosen exercise together with its set, started or not, and returns the dropped set.
+func (s *Service) CancelSet(ctx context.Context, id int64) (domain.Set, error) {
+
+	var set domain.Set
+	err := s.Tx.Do(ctx, func(ctx context.Context) error {
+
+		var err error
+		set, err = s.Sets.LockOpenSet(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.Sets.DeleteSet(ctx, set.Set_id)
+	})
+	if err != nil {
+		return domain.Set{}, err
+	}
+
+	return set, nil
+}
+
+func (s *Service) StartSet(ctx context.Context, id int64, startTime time.Time) error {
+
+	if err := s.requireActiveTraining(ctx, id); err != nil {
+		return err
+	}
+
+	isChosen, err := s.Sets.IsExerciseChoosen(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !isChosen {
+		return domain.ErrExerciseNotChosen
+	}
+
+	return s.Sets.StartSet(ctx, id, startTime)
+}
+
+// RequireOpenSet returns ErrNoOpenSet unless the user has a started and not yet finished set.
+func (s *Service) RequireOpenSet(ctx context.Context, id int64) error {
+
+	isStarted, err := s.Sets.IsSetStarted(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !isStarted {
+		return domain.ErrNoOpenSet
+	}
+
+	return nil
+}
+
+// FinishSet records the end time, weight and reps of the running set atomically and returns its id.
+func (s *Service) FinishSet(ctx context.Context, id int64, endTime time.Time, weight float64, reps int) (int64, error) {
+
+	if err := validateWeight(weight); err != nil {
+		return 0, err
+	}
+
+	if err := validateReps(reps); err != nil {
+		return 0, err
+	}
+
+	var setID int64
+	err := s.Tx.Do(ctx, func(ctx context.Context) error {
+
+		set, err := s.Sets.LockOpenSet(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if set.Start.IsZero() {
+			return domain.ErrNoOpenSet
+		}
+
+		setID = set.Set_id
+
+		return s.Sets.FinishSet(ctx, set.Set_id, endTime, weight, reps)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return setID, nil
+}
+
+// Limits of a set entry: MaxSetsPerEntry is the most identical sets RecordSets and LogSets record
+// at once, anything above MaxWeight kg or MaxReps reps is taken for a typo.
+const (
+	MaxSetsPerEntry = 10
+	MaxWeight       = 1000
+	MaxReps         = 1000
+)
+
+// RequireUntimedSet returns ErrExerciseNotChosen unless an exercise is chosen, and ErrSetAlreadyOpen
+// if its set is already being timed, so it has to be finished with FinishSet.
+func (s *Service) RequireUntimedSet(ctx context.Context, id int64) error {
+
+	isChosen, err := s.Sets.IsExerciseChoosen(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !isChosen {
+		return domain.ErrExerciseNotChosen
+	}
+
+	isStarted, err := s.Sets.IsSetStarted(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if isStarted {
+		return domain.ErrSetAlreadyOpen
+	}
+
+	return nil
+}
+
+// RecordSets records count identical sets of the chosen exercise without timing them and returns their
+// ids. The open set is replaced by the new sets, so their ids follow each other and UndoSets removes
+// them all.
+func (s *Service) RecordSets(ctx context.Context, id int64, weight float64, reps, count int) ([]int64, error) {
+
+	if err := validateSetEntry(weight, reps, count); err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	err := s.Tx.Do(ctx, func(ctx context.Context) error {
+
+		set, err := s.Sets.LockOpenSet(ctx, id)
+		if errors.Is(err, domain.ErrNoOpenSet) {
+			return domain.ErrExerciseNotChosen
+		}
+		if err != nil {
+			return err
+		}
+
+		if !set.Start.IsZero() {
+			return domain.ErrSetAlreadyOpen
+		}
+
+		if err := s.Sets.DeleteSet(ctx, set.Set_id); err != nil {
+			return err
+		}
+
+		ids = nil
+		for i := 0; i < count; i++ {
+			setID, err := s.Sets.AddSet(ctx, domain.Set{
+				User_id:     id,
+				Training_id: set.Training_id,
+				Exercise:    set.Exercise,
+				Weight:      weight,
+				Reps:        reps,
+			})
+			if err != nil {
+				return err
+			}
+			ids = append(ids, setID)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// LogSets records count identical sets of the exercise in the active training without timing them and
+// returns their ids. The chosen or running set is left as it is.
+func (s *Service) LogSets(ctx context.Context, id, exerciseID int64, weight float64, reps, count int) ([]int64, error) {
+	return s.logSets(ctx, id, exerciseID, weight, reps, count, s.Trainings.GetActiveTraining)
+}
+
+// logSets adds the sets to the training the given function loads inside the transaction.
+func (s *Service) logSets(ctx context.Context, id, exerciseID int64, weight float64, reps, count int,
+	loadTraining func(ctx context.Context, id int64) (domain.Training, error)) ([]int64, error) {
+
+	if err := validateSetEntry(weight, reps, count); err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	err := s.Tx.Do(ctx, func(ctx context.Context) error {
+
+		training, err := loadTraining(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		exercise, err := s.activeExercise(ctx, id, exerciseID)
+		if err != nil {
+			return err
+		}
+
+		ids = nil
+		for i := 0; i < count; i++ {
+			setID, err := s.Sets.AddSet(ctx, domain.Set{
+				User_id:     id,
+				Training_id: training.Training_id,
+				Exercise:    exercise.Name,
+				Weight:      weight,
+				Reps:        reps,
+			})
+			if err != nil {
+				return err
+			}
+			ids = append(ids, setID)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// activeExercise returns the user's exercise, ErrUnknownExercise if it is archived.
+func (s *Service) activeExercise(ctx context.Context, id, exerciseID int64) (domain.Exercise, error) {
+
+	exercise, err := s.Exercises.GetExercise(ctx, id, exerciseID)
+	if err != nil {
+		return domain.Exercise{}, err
+	}
+
+	if exercise.Archived {
+		return domain.Exercise{}, domain.ErrUnknownExercise
+	}
+
+	return exercise, nil
+}
+
+// UndoSets removes the user's recorded sets with ids from fromSetID to toSetID, like the ones LogSets
+// returned, and returns how many were removed. Nothing is removed if they are already gone.
+func (s *Service) UndoSets(ctx context.Context, id, fromSetID, toSetID int64) (int64, error) {
+	return s.Sets.DeleteSets(ctx, id, fromSetID, toSetID)
+}
+
+func validateSetEntry(weight float64, reps, count int) error {
+
+	if err := validateWeight(weight); err != nil {
+		return err
+	}
+
+	if err := validateReps(reps); err != nil {
+		return err
+	}
+
+	if count < 1 || count > MaxSetsPerEntry {
+		return domain.ErrInvalidSetCount
+	}
+
+	return nil
+}
+
+func validateWeight(weight float64) error {
+
+	if weight < 0 || weight > MaxWeight {
+		return domain.ErrInvalidWeight
+	}
+
+	return nil
+}
+
+func validateReps(reps int) error {
+
+	if reps <= 0 || reps > MaxReps {
+		return domain.ErrInvalidReps
+	}
+
+	return nil
+}
+
+// AddExercise normalizes the name and adds the exercise to the user's catalog. It returns the name
+// the exercise was stored under, or with ErrExerciseExists the name of the existing look-alike exercise.
+func (s *Service) AddExercise(ctx context.Context, id int64, exercise string) (string, error) {
+
+	name, err := domain.NormalizeExerciseName(exercise)
+	if err != nil {
+		return "", err
+	}
+
+	existing, found, err := s.findExercise(ctx, id, name)
+	if err != nil {
+		return "", err
+	}
+
+	if found {
+		return existing.Name, domain.ErrExerciseExists
+	}
+
+	err = s.Exercises.AddExercise(ctx, id, name)
+	if errors.Is(err, domain.ErrExerciseExists) {
+		// Added by a concurrent update after the lookup above
+		if existing, found, err := s.findExercise(ctx, id, name); err == nil && found {
+			return existing.Name, domain.ErrExerciseExists
+		}
+
+		return name, domain.ErrExerciseExists
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// ExercisePage returns the requested page of the picker in the given order. An unknown order
+// lists the exercises in the order they were added.
+func (s *Service) ExercisePage(ctx context.Context, id int64, order domain.ExerciseOrder, page int64) (Page[domain.Exercise], error) {
+
+	if !order.Valid() {
+		order = domain.OrderAdded
+	}
+
+	return paginate(ctx, page, s.PageSize,
+		func(ctx context.Context) (int64, error) {
+			return s.Exercises.CountExercises(ctx, id)
+		},
+		func(ctx context.Context, offset, limit int64) ([]domain.Exercise, error) {
+			return s.Exercises.GetPage(ctx, id, order, offset, limit)
+		})
+}
+
+// PinnedExercises returns the exercises shown above the first page of the picker: the favourites,
+// then the other exercises of the current or last training. There are at most PinnedLimit of them.
+func (s *Service) PinnedExercises(ctx context.Context, id int64) ([]domain.Exercise, error) {
+
+	favorites, err := s.Exercises.GetFavoriteExercises(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	recent, err := s.Exercises.GetLastTrainingExercises(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	pinned := favorites
+	for _, e := range recent {
+		if !e.Favorite {
+			pinned = append(pinned, e)
+		}
+	}
+
+	return pinned[:min(len(pinned), PinnedLimit)], nil
+}
+
+func (s *Service) requireActiveTraining(ctx context.Context, id int64) error {
+
+	isActive, err := s.Trainings.IsTrainingActive(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !isActive {
+		return domain.ErrNoActiveTraining
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package application
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"GymBot/internal/domain/repository"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestValidateSetEntry(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		weight float64
+		reps   int
+		count  int
+		want   error
+	}{
+		{"bodyweight", 0, 10, 1, nil},
+		{"heaviest", MaxWeight, 1, 1, nil},
+		{"most reps and sets", 100, MaxReps, MaxSetsPerEntry, nil},
+		{"negative weight", -1, 5, 1, domain.ErrInvalidWeight},
+		{"too heavy", MaxWeight + 0.5, 5, 1, domain.ErrInvalidWeight},
+		{"no reps", 100, 0, 1, domain.ErrInvalidReps},
+		{"too many reps", 100, MaxReps + 1, 1, domain.ErrInvalidReps},
+		{"reps past INTEGER", 100, 1 << 40, 1, domain.ErrInvalidReps},
+		{"no sets", 100, 5, 0, domain.ErrInvalidSetCount},
+		{"too many sets", 100, 5, MaxSetsPerEntry + 1, domain.ErrInvalidSetCount},
+	}
+
+	for _, tt := range tests {
+		if err := validateSetEntry(tt.weight, tt.reps, tt.count); !errors.Is(err, tt.want) {
+			t.Errorf("%s: validateSetEntry(%v, %d, %d) = %v, want %v", tt.name, tt.weight, tt.reps, tt.count, err, tt.want)
+		}
+	}
+}
+
+// racingExercises adds the exercise under another spelling right before the add, like a concurrent update would.
+type racingExercises struct {
+	repository.ExerciseRepository
+	spelling string
+}
+
+func (r racingExercises) AddExercise(ctx context.Context, id int64, exercise string) error {
+
+	if err := r.ExerciseRepository.AddExercise(ctx, id, r.spelling); err != nil {
+		return err
+	}
+
+	return r.ExerciseRepository.AddExercise(ctx, id, exercise)
+}
+
+func TestAddExerciseRace(t *testing.T) {
+
+	s, st := newService(t)
+	s.Exercises = racingExercises{ExerciseRepository: st, spelling: "Присед"}
+
+	name, err := s.AddExercise(context.Background(), testUser, "присед")
+	if !errors.Is(err, domain.ErrExerciseExists) || name != "Присед" {
+		t.Errorf("AddExercise = %q, %v, want %q, %v", name, err, "Присед", domain.ErrExerciseExists)
+	}
+}
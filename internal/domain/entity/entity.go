@@ -7,21 +7,34 @@ type User struct {
 }
 
 type Set struct {
-	user_id  int64
-	Exercise string
-	Reps     int
-	Weight   float64
-	Start    time.Time
-	End      time.Time
+	Set_id      int64
+	User_id     int64
+	Training_id int64
+	Exercise    string
+	Reps        int
+	Weight      float64
+	Start       time.Time // zero until the set is started
+	End         time.Time
 }
 
 type Training struct {
-	User_id int64
-	Start   time.Time
-	End     time.Time
+	Training_id int64
+	User_id     int64
+	Start       time.Time
+	End         time.Time // zero while the training is active
 }
 
 type Exercise struct {
-	user_id       int64
-	exercise_name string
+	Exercise_id int64
+	User_id     int64
+	Name        string
+	Archived    bool
+	Favorite    bool
+}
+
+type Dialog struct {
+	User_id   int64
+	State     string
+	Data      map[string]string
+	ExpiresAt time.Time
 }
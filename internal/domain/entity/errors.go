@@ -0,0 +1,29 @@
+package domain
+
+import "errors"
+
+var (
+	ErrExerciseNotChosen     = errors.New("exercise is not chosen")
+	ErrUnknownExercise       = errors.New("exercise does not exist")
+	ErrEmptyExerciseName     = errors.New("exercise name is empty")
+	ErrInvalidExerciseName   = errors.New("exercise name is invalid")
+	ErrExerciseNameTooShort  = errors.New("exercise name is too short")
+	ErrExerciseNameTooLong   = errors.New("exercise name is too long")
+	ErrExerciseExists        = errors.New("exercise already exists")
+	ErrExerciseInUse         = errors.New("exercise has recorded sets")
+	ErrExerciseChosen        = errors.New("exercise is chosen for the open set")
+	ErrMergeIntoItself       = errors.New("exercise cannot be merged into itself")
+	ErrInvalidWeight         = errors.New("weight is out of range")
+	ErrInvalidReps           = errors.New("reps are out of range")
+	ErrInvalidSetCount       = errors.New("set count is out of range")
+	ErrTrainingAlreadyActive = errors.New("training is already active")
+	ErrNoActiveTraining      = errors.New("no active training")
+	ErrUnknownTraining       = errors.New("training does not exist")
+	ErrInvalidDuration       = errors.New("training duration is out of range")
+	ErrTrainingInFuture      = errors.New("training ends in the future")
+	ErrTrainingOverlaps      = errors.New("training overlaps another training")
+	ErrEmptyTraining         = errors.New("training has no sets")
+	ErrNoOpenSet             = errors.New("no open set")
+	ErrSetAlreadyOpen        = errors.New("set is already open")
+	ErrUnknownSet            = errors.New("set does not exist")
+)
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MinExerciseNameLength = 2
+	MaxExerciseNameLength = 28 // longer names do not fit on a button
+)
+
+// lookAlikes maps lower-case Latin letters to the Cyrillic letters they look like, so that
+// "Присед" typed with a Latin "р" or "е" is still the same exercise. ё is compared as е.
+var lookAlikes = strings.NewReplacer(
+	"a", "а", "b", "в", "c", "с", "e", "е", "h", "н", "k", "к", "m", "м",
+	"o", "о", "p", "р", "t", "т", "x", "х", "y", "у", "ё", "е",
+)
+
+// NormalizeExerciseName trims the name and collapses inner whitespace, then checks that it is
+// usable as an exercise name.
+func NormalizeExerciseName(name string) (string, error) {
+
+	name = strings.Join(strings.Fields(name), " ")
+
+	switch {
+	case name == "":
+		return "", ErrEmptyExerciseName
+	case strings.HasPrefix(name, "/") || !strings.ContainsFunc(name, isLetterOrDigit):
+		return "", ErrInvalidExerciseName
+	case utf8.RuneCountInString(name) < MinExerciseNameLength:
+		return "", ErrExerciseNameTooShort
+	case utf8.RuneCountInString(name) > MaxExerciseNameLength:
+		return "", ErrExerciseNameTooLong
+	}
+
+	return name, nil
+}
+
+// ExerciseNameKey returns the form names are compared in: two names with the same key
+// are the same exercise. The name is expected to be normalized already.
+func ExerciseNameKey(name string) string {
+	return lookAlikes.Replace(strings.ToLower(name))
+}
+
+func isLetterOrDigit(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeExerciseName(t *testing.T) {
+
+	tests := []struct {
+		name string
+		want string
+		err  error
+	}{
+		{"Присед", "Присед", nil},
+		{"  Жим   лежа \t", "Жим лежа", nil},
+		{"Жим лежа", "Жим лежа", nil},
+		{"Squat 2", "Squat 2", nil},
+		{"   ", "", ErrEmptyExerciseName},
+		{"/start", "", ErrInvalidExerciseName},
+		{"---", "", ErrInvalidExerciseName},
+		{"Ж", "", ErrExerciseNameTooShort},
+		{strings.Repeat("ж", MaxExerciseNameLength), strings.Repeat("ж", MaxExerciseNameLength), nil},
+		{strings.Repeat("ж", MaxExerciseNameLength+1), "", ErrExerciseNameTooLong},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeExerciseName(tt.name)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("NormalizeExerciseName(%q) = %q, %v, want %q, %v", tt.name, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestExerciseNameKey(t *testing.T) {
+
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"Присед", "присед", true},
+		{"ПРИСЕД", "присед", true},
+		{"Пpисeд", "Присед", true}, // Latin р and е
+		{"Жим ёлки", "жим елки", true},
+		{"Squat", "squat", true},
+		{"Жим", "Жим лежа", false},
+		{"Тяга", "Тяга 2", false},
+	}
+
+	for _, tt := range tests {
+		if same := ExerciseNameKey(tt.a) == ExerciseNameKey(tt.b); same != tt.same {
+			t.Errorf("ExerciseNameKey(%q) == ExerciseNameKey(%q) is %v, want %v", tt.a, tt.b, same, tt.same)
+		}
+	}
+}
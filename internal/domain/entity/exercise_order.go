@@ -0,0 +1,24 @@
+package domain
+
+// ExerciseOrder is the order the exercise picker lists exercises in.
+type ExerciseOrder string
+
+const (
+	OrderAdded    ExerciseOrder = "added"  // the order they were added in
+	OrderName     ExerciseOrder = "name"   // alphabetical
+	OrderMostUsed ExerciseOrder = "used"   // most recorded sets first
+	OrderRecent   ExerciseOrder = "recent" // the ones done in the latest trainings first
+)
+
+// ExerciseOrders lists the orders in the order they are offered to the user.
+var ExerciseOrders = []ExerciseOrder{OrderAdded, OrderName, OrderMostUsed, OrderRecent}
+
+func (o ExerciseOrder) Valid() bool {
+	for _, order := range ExerciseOrders {
+		if o == order {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MatchExerciseName reports whether the name matches the search query and how many typos it
+// took: 0 means the query is a part of the name. Both are compared by ExerciseNameKey, and the
+// longer the query, the more typos are tolerated.
+func MatchExerciseName(query, name string) (int, bool) {
+
+	q := ExerciseNameKey(strings.Join(strings.Fields(query), " "))
+	n := ExerciseNameKey(name)
+
+	if q == "" {
+		return 0, false
+	}
+
+	if strings.Contains(n, q) {
+		return 0, true
+	}
+
+	typos := substringDistance([]rune(q), []rune(n))
+
+	return typos, typos <= allowedTypos(utf8.RuneCountInString(q))
+}
+
+// allowedTypos is one typo per four letters of the query, short queries must match exactly.
+func allowedTypos(queryLength int) int {
+	return queryLength / 4
+}
+
+// substringDistance is the smallest edit distance between the query and any part of the name.
+// Swapped neighbouring letters count as one typo.
+func substringDistance(query, name []rune) int {
+
+	// rows[i][j] is the distance of the first i query letters to a part of the name ending at name[j-1],
+	// a match may start anywhere in the name. Only the last three rows are kept.
+	var rows [3][]int
+	for i := range rows {
+		rows[i] = make([]int, len(name)+1)
+	}
+
+	for i := 1; i <= len(query); i++ {
+		cur, prev, prev2 := rows[i%3], rows[(i-1)%3], rows[(i-2+3)%3]
+
+		cur[0] = i
+		for j := 1; j <= len(name); j++ {
+			cost := 1
+			if query[i-1] == name[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j-1]+cost, prev[j]+1, cur[j-1]+1)
+
+			if i > 1 && j > 1 && query[i-1] == name[j-2] && query[i-2] == name[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+	}
+
+	best := len(query)
+	for _, d := range rows[len(query)%3] {
+		best = min(best, d)
+	}
+
+	return best
+}
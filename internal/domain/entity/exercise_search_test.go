@@ -0,0 +1,77 @@
+package domain
+
+import "testing"
+
+func TestMatchExerciseName(t *testing.T) {
+
+	tests := []struct {
+		query, name string
+		typos       int
+		ok          bool
+	}{
+		{"жим", "Жим лежа", 0, true},
+		{"лежа", "Жим лежа", 0, true},
+		{"  жим   лежа ", "Жим лежа", 0, true},
+		{"жИм ЛёЖа", "ЖИМ ЛЕЖА", 0, true},
+		{"пресед", "Присед", 1, true},
+		{"пирсед", "Присед", 1, true},
+		{"жим лежя", "Жим лежа на скамье", 1, true},
+		{"пресад", "Присед", 2, false},
+		{"стоновоя тега", "Становая тяга", 3, true},
+		{"стоновоя тегу", "Становая тяга", 4, false},
+		{"жмм", "Жим", 1, false},
+		{"ким", "Жим", 1, false},
+		{"жим", "Жим", 0, true},
+		{"", "Жим", 0, false},
+	}
+
+	for _, tt := range tests {
+		typos, ok := MatchExerciseName(tt.query, tt.name)
+		if typos != tt.typos || ok != tt.ok {
+			t.Errorf("MatchExerciseName(%q, %q) = %d, %t, want %d, %t", tt.query, tt.name, typos, ok, tt.typos, tt.ok)
+		}
+	}
+}
+
+func TestAllowedTypos(t *testing.T) {
+
+	tests := []struct {
+		length, want int
+	}{
+		{1, 0},
+		{3, 0},
+		{4, 1},
+		{7, 1},
+		{8, 2},
+		{13, 3},
+	}
+
+	for _, tt := range tests {
+		if got := allowedTypos(tt.length); got != tt.want {
+			t.Errorf("allowedTypos(%d) = %d, want %d", tt.length, got, tt.want)
+		}
+	}
+}
+
+func TestSubstringDistance(t *testing.T) {
+
+	tests := []struct {
+		query, name string
+		want        int
+	}{
+		{"сед", "присед", 0},
+		{"присед", "присед", 0},
+		{"прсед", "присед", 1},
+		{"приисед", "присед", 1},
+		{"пирсед", "присед", 1},
+		{"рпсиед", "присед", 2},
+		{"тяга", "жим", 4},
+		{"жим", "", 3},
+	}
+
+	for _, tt := range tests {
+		if got := substringDistance([]rune(tt.query), []rune(tt.name)); got != tt.want {
+			t.Errorf("substringDistance(%q, %q) = %d, want %d", tt.query, tt.name, got, tt.want)
+		}
+	}
+}
@@ -2,37 +2,92 @@ package repository
 
 import (
 	domain "GymBot/internal/domain/entity"
+	"context"
 	"time"
 )
 
+// UnitOfWork runs several repository calls atomically: every call made with the ctx passed to fn
+// belongs to one transaction, which is rolled back if fn returns an error.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type UserRepository interface {
-	StartTrainig(id int64, startTime time.Time) error
-	EndTraining(id int64, endTime time.Time) error
-	StartSet(id int64, startTime time.Time) error
-	EndSet(id int64, endTime time.Time) error
-	SetWeight(id int64, weight float64) error
-	SetReps(id int64, reps int) error
-	SetExercise(id int64, exercise string) error
-	AddExercise(id int64, exercise string) error
-	UserCheck(id int64) (bool, error)
-	IsExerciseChoosen(id int64) (bool, error)
-	RegisterUser(id int64) error
-	MaxExerciseId(id int64) (int, error)
-	MaxPages(id int64) (int64, error)
-	GetPage(id, page int64) ([]string, error)
-	IsTrainingActive(id int64) (bool, error)
-	GetMostPopularExercise(id int64) (string, error)
-	GetLeastPopularExercise(id int64) (string, error)
-	GetAverageWeight(id int64, exercise string) (float64, error)
-	GetAverageReps(id int64, exercise string) (string, error)
-	GetAverageTrainingsLenght(id int64) (time.Duration, error)
-	GetTrainingsCount(id int64) (int64, error)
-	GetTotalSetsPerExercise(id int64, exercise string) (int64, error)
-	GetAverageSetsPerTraining(id int64) (float64, error) //Среднее колличество сэтов за тренировку
-	GetTrainings(id int64) ([]domain.Training, error)
-	GetExercises(id int64) ([]string, error)
-	GetSetsCount(training domain.Training, exercise string) (int, error)
-	GetAverageExercisesPerTraining(id int64) (float64, error)
-	GenerateExelStats(id int64, userName string) (string, error)
-	GetAverageSetsPerExerise(id int64, exercise string) (string, error)
+	RegisterUser(ctx context.Context, id int64) error
+	UserCheck(ctx context.Context, id int64) (bool, error)
+}
+
+type TrainingRepository interface {
+	StartTrainig(ctx context.Context, id int64, startTime time.Time) error
+	EndTraining(ctx context.Context, id int64, endTime time.Time) error
+	IsTrainingActive(ctx context.Context, id int64) (bool, error)
+	// GetActiveTraining returns the user's training that has not ended, or ErrNoActiveTraining.
+	GetActiveTraining(ctx context.Context, id int64) (domain.Training, error)
+	GetTrainings(ctx context.Context, id int64) ([]domain.Training, error)
+	// GetTraining returns the user's training, ErrUnknownTraining if the user has no such training.
+	GetTraining(ctx context.Context, id, trainingID int64) (domain.Training, error)
+	// AddTraining inserts a finished training with its start and end times and returns its id.
+	AddTraining(ctx context.Context, training domain.Training) (int64, error)
+}
+
+type SetRepository interface {
+	SetExercise(ctx context.Context, id int64, exercise string) error
+	StartSet(ctx context.Context, id int64, startTime time.Time) error
+	IsExerciseChoosen(ctx context.Context, id int64) (bool, error)
+	IsSetStarted(ctx context.Context, id int64) (bool, error)
+	LockOpenSet(ctx context.Context, id int64) (domain.Set, error)
+	FinishSet(ctx context.Context, setID int64, endTime time.Time, weight float64, reps int) error
+	// AddSet inserts an already recorded set and returns its id. Zero start and end times mean the set was not timed.
+	AddSet(ctx context.Context, set domain.Set) (int64, error)
+	// GetSet returns the user's recorded set, ErrUnknownSet if the user has no such set.
+	GetSet(ctx context.Context, id, setID int64) (domain.Set, error)
+	// GetTrainingSets returns the recorded sets of the user's training ordered by set id, that is in the order
+	// they were added: a set started before another one but finished after it still comes first.
+	GetTrainingSets(ctx context.Context, id, trainingID int64) ([]domain.Set, error)
+	// UpdateSet changes the exercise, weight and reps of the user's set with the set's id.
+	UpdateSet(ctx context.Context, set domain.Set) error
+	DeleteSet(ctx context.Context, setID int64) error
+	// DeleteSets removes the user's recorded sets with ids from fromSetID to toSetID and returns how many were removed.
+	DeleteSets(ctx context.Context, id, fromSetID, toSetID int64) (int64, error)
+	RenameExerciseSets(ctx context.Context, id int64, exercise, newName string) error
+}
+
+type ExerciseRepository interface {
+	AddExercise(ctx context.Context, id int64, exercise string) error
+	// GetExercise returns the user's exercise, or ErrUnknownExercise if there is none with that id or it belongs to another user.
+	GetExercise(ctx context.Context, id, exerciseID int64) (domain.Exercise, error)
+	GetExercises(ctx context.Context, id int64) ([]domain.Exercise, error)
+	CountExercises(ctx context.Context, id int64) (int64, error)
+	// GetPage returns up to limit active exercises of the user in the given order, skipping offset of them.
+	GetPage(ctx context.Context, id int64, order domain.ExerciseOrder, offset, limit int64) ([]domain.Exercise, error)
+	GetArchivedExercises(ctx context.Context, id int64) ([]domain.Exercise, error)
+	// GetFavoriteExercises returns the active favourite exercises of the user sorted by name.
+	GetFavoriteExercises(ctx context.Context, id int64) ([]domain.Exercise, error)
+	// GetLastTrainingExercises returns the active exercises done in the user's latest training, current one
+	// included, in the order they were first done in it.
+	GetLastTrainingExercises(ctx context.Context, id int64) ([]domain.Exercise, error)
+	RenameExercise(ctx context.Context, id, exerciseID int64, newName string) error
+	SetExerciseArchived(ctx context.Context, id, exerciseID int64, archived bool) error
+	SetExerciseFavorite(ctx context.Context, id, exerciseID int64, favorite bool) error
+	DeleteExercise(ctx context.Context, id, exerciseID int64) error
+}
+
+type StatsRepository interface {
+	GetMostPopularExercise(ctx context.Context, id int64) (string, error)
+	GetLeastPopularExercise(ctx context.Context, id int64) (string, error)
+	GetAverageWeight(ctx context.Context, id int64, exercise string) (float64, error)
+	GetAverageReps(ctx context.Context, id int64, exercise string) (string, error)
+	GetAverageTrainingsLenght(ctx context.Context, id int64) (time.Duration, error)
+	GetTrainingsCount(ctx context.Context, id int64) (int64, error)
+	GetTotalSetsPerExercise(ctx context.Context, id int64, exercise string) (int64, error)
+	GetAverageSetsPerTraining(ctx context.Context, id int64) (float64, error) //Среднее колличество сэтов за тренировку
+	GetSetsCount(ctx context.Context, training domain.Training, exercise string) (int, error)
+	GetAverageExercisesPerTraining(ctx context.Context, id int64) (float64, error)
+	GetAverageSetsPerExerise(ctx context.Context, id int64, exercise string) (string, error)
+}
+
+type DialogRepository interface {
+	GetDialog(ctx context.Context, id int64) (*domain.Dialog, error)
+	SaveDialog(ctx context.Context, dialog domain.Dialog) error
+	DeleteDialog(ctx context.Context, id int64) error
 }
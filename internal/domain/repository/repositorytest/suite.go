@@ -0,0 +1,648 @@
+// Package repositorytest is a conformance suite for the repository interfaces. Every storage backend
+// runs it from its own tests, so they all behave the same way the application relies on.
+package repositorytest
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"GymBot/internal/domain/repository"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Storage is the set of repositories a backend provides.
+type Storage struct {
+	Users     repository.UserRepository
+	Trainings repository.TrainingRepository
+	Sets      repository.SetRepository
+	Exercises repository.ExerciseRepository
+	Stats     repository.StatsRepository
+	Dialogs   repository.DialogRepository
+	Tx        repository.UnitOfWork
+}
+
+// Run runs the suite. newStorage is called for every test and must return a storage without any data.
+func Run(t *testing.T, newStorage func(t *testing.T) Storage) {
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Storage)
+	}{
+		{"Users", testUsers},
+		{"Trainings", testTrainings},
+		{"PastTrainings", testPastTrainings},
+		{"TimedSet", testTimedSet},
+		{"RecordedSets", testRecordedSets},
+		{"Exercises", testExercises},
+		{"ExercisePages", testExercisePages},
+		{"Stats", testStats},
+		{"EmptyStats", testEmptyStats},
+		{"Dialogs", testDialogs},
+		{"Tx", testTx},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStorage(t))
+		})
+	}
+}
+
+const (
+	user  int64 = 1
+	other int64 = 2
+)
+
+// base is the start of the first training, whole seconds in UTC survive every backend unchanged.
+var base = time.Date(2026, time.January, 10, 18, 0, 0, 0, time.UTC)
+
+func testUsers(t *testing.T, s Storage) {
+
+	ctx := context.Background()
+
+	ok, err := s.Users.UserCheck(ctx, user)
+	check(t, err)
+	if ok {
+		t.Fatal("UserCheck before RegisterUser = true")
+	}
+
+	check(t, s.Users.RegisterUser(ctx, user))
+
+	ok, err = s.Users.UserCheck(ctx, user)
+	check(t, err)
+	if !ok {
+		t.Fatal("UserCheck after RegisterUser = false")
+	}
+}
+
+func testTrainings(t *testing.T, s Storage) {
+
+	ctx := context.Background()
+	register(t, s, user, other)
+
+	if _, err := s.Trainings.GetActiveTraining(ctx, user); !errors.Is(err, domain.ErrNoActiveTraining) {
+		t.Fatalf("GetActiveTraining without a training: %v, want ErrNoActiveTraining", err)
+	}
+
+	check(t, s.Trainings.StartTrainig(ctx, user, base))
+	if err := s.Trainings.StartTrainig(ctx, user, base.Add(time.Minute)); !errors.Is(err, domain.ErrTrainingAlreadyActive) {
+		t.Fatalf("second StartTrainig: %v, want ErrTrainingAlreadyActive", err)
+	}
+
+	active, err := s.Trainings.IsTrainingActive(ctx, user)
+	check(t, err)
+	if !active {
+		t.Fatal("IsTrainingActive = false")
+	}
+
+	training, err := s.Trainings.GetActiveTraining(ctx, user)
+	check(t, err)
+	if training.User_id != user || !training.Start.Equal(base) || !training.End.IsZero() {
+		t.Fatalf("GetActiveTraining = %+v", training)
+	}
+
+	if _, err := s.Trainings.GetTraining(ctx, other, training.Training_id); !errors.Is(err, domain.ErrUnknownTraining) {
+		t.Fatalf("GetTraining of another user: %v, want ErrUnknownTraining", err)
+	}
+
+	check(t, s.Trainings.EndTraining(ctx, user, base.Add(time.Hour)))
+
+	active, err = s.Trainings.IsTrainingActive(ctx, user)
+	check(t, err)
+	if active {
+		t.Fatal("IsTrainingActive after EndTraining = true")
+	}
+
+	ended, err := s.Trainings.GetTraining(ctx, user, training.Training_id)
+	check(t, err)
+	if !ended.End.Equal(base.Add(time.Hour)) {
+		t.Fatalf("End = %v, want %v", ended.End, base.Add(time.Hour))
+	}
+
+	check(t, s.Trainings.StartTrainig(ctx, user, base.Add(24*time.Hour)))
+
+	count, err := s.Stats.GetTrainingsCount(ctx, user)
+	check(t, err)
+	if count != 2 {
+		t.Fatalf("GetTrainingsCount = %d, want 2", count)
+	}
+}
+
+func testPastTrainings(t *testing.T, s Storage) {
+
+	ctx := context.Background()
+	register(t, s, user)
+
+	check(t, s.Trainings.StartTrainig(ctx, user, base.Add(48*time.Hour)))
+
+	id, err := s.Trainings.AddTraining(ctx, domain.Training{
+		User_id: user,
+		Start:   base,
+		End:     base.Add(90 * time.Minute),
+	})
+	check(t, err)
+
+	past, err := s.Trainings.GetTraining(ctx, user, id)
+	check(t, err)
+	if !past.Start.Equal(base) || !past.End.Equal(base.Add(90*time.Minute)) {
+		t.Fatalf("GetTraining = %+v", past)
+	}
+
+	// A past training is not the active one and is listed by its start time, not the order it was added in
+	active, err := s.Trainings.GetActiveTraining(ctx, user)
+	check(t, err)
+	if active.Training_id == id {
+		t.Fatal("the past training is returned as the active one")
+	}
+
+	trainings, err := s.Trainings.GetTrainings(ctx, user)
+	check(t, err)
+	if len(trainings) != 2 || trainings[0].Training_id != id || trainings[1].Training_id != active.Training_id {
+		t.Fatalf("GetTrainings = %+v, want the past training first", trainings)
+	}
+}
+
+func testTimedSet(t *testing.T, s Storage) {
+
+	ctx := context.Background()
+	register(t, s, user)
+
+	if err := s.Sets.SetExercise(ctx, user, "Присед"); !errors.Is(err, domain.ErrNoActiveTraining) {
+		t.Fatalf("SetExercise without a training: %v, want ErrNoActiveTraining", err)
+	}
+
+	check(t, s.Trainings.StartTrainig(ctx, user, base))
+
+	if _, err := s.Sets.LockOpenSet(ctx, user); !errors.Is(err, domain.ErrNoOpenSet) {
+		t.Fatalf("LockOpenSet without a set: %v, want ErrNoOpenSet", err)
+	}
+
+	check(t, s.Sets.SetExercise(ctx, user, "Присед"))
+	if err := s.Sets.SetExercise(ctx, user, "Жим"); !errors.Is(err, domain.ErrSetAlreadyOpen) {
+		t.Fatalf("second SetExercise: %v, want ErrSetAlreadyOpen", err)
+	}
+
+	chosen, err := s.Sets.IsExerciseChoosen(ctx, user)
+	check(t, err)
+	started, err := s.Sets.IsSetStarted(ctx, user)
+	check(t, err)
+	if !chosen || started {
+		t.Fatalf("after SetExercise chosen = %v, started = %v", chosen, started)
+	}
+
+	check(t, s.Sets.StartSet(ctx, user, base.Add(time.Minute)))
+
+	started, err = s.Sets.IsSetStarted(ctx, user)
+	check(t, err)
+	if !started {
+		t.Fatal("IsSetStarted after StartSet = false")
+	}
+
+	open, err := s.Sets.LockOpenSet(ctx, user)
+	check(t, err)
+	if open.Exercise != "Присед" || !open.Start.Equal(base.Add(time.Minute)) {
+		t.Fatalf("LockOpenSet = %+v", open)
+	}
+
+	// The open set is not listed until it is recorded
+	if _, err := s.Sets.GetSet(ctx, user, open.Set_id); !errors.Is(err, domain.ErrUnknownSet) {
+		t.Fatalf("GetSet of the open set: %v, want ErrUnknownSet", err)
+	}
+
+	check(t, s.Sets.FinishSet(ctx, open.Set_id, base.Add(2*time.Minute), 100, 5))
+
+	chosen, err = s.Sets.IsExerciseChoosen(ctx, user)
+	check(t, err)
+	if chosen {
+		t.Fatal("IsExerciseChoosen after FinishSet = true")
+	}
+
+	set, err := s.Sets.GetSet(ctx, user, open.Set_id)
+	check(t, err)
+	if set.Exercise != "Присед" || set.Weight != 100 || set.Reps != 5 || !set.End.Equal(base.Add(2*time.Minute)) {
+		t.Fatalf("GetSet = %+v", set)
+	}
+}
+
+func testRecordedSets(t *testing.T, s Storage) {
+
+	ctx := context.Background()
+	register(t, s, user, other)
+
+	check(t, s.Trainings.StartTrainig(ctx, user, base))
+	training, err := s.Trainings.GetActiveTraining(ctx, user)
+	check(t, err)
+
+	check(t, s.Sets.SetExercise(ctx, user, "Присед"))
+	open, err := s.Sets.LockOpenSet(ctx, user)
+	check(t, err)
+	check(t, s.Sets.FinishSet(ctx, open.Set_id, base.Add(time.Minute), 100, 5))
+
+	var ids []int64
+	for i := 0; i < 3; i++ {
+		id, err := s.Sets.AddSet(ctx, domain.Set{
+			User_id:     user,
+			Training_id: training.Training_id,
+			Exercise:    "Жим",
+			Weight:      60,
+			Reps:        8,
+		})
+		check(t, err)
+		ids = append(ids, id)
+	}
+
+	sets, err := s.Sets.GetTrainingSets(ctx, user, training.Training_id)
+	check(t, err)
+	if len(sets) != 4 {
+		t.Fatalf("GetTrainingSets returned %d sets, want 4", len(sets))
+	}
+
+	if _, err := s.Sets.GetSet(ctx, other, ids[0]); !errors.Is(err, domain.ErrUnknownSet) {
+		t.Fatalf("GetSet of another user: %v, want ErrUnknownSet", err)
+	}
+
+	set, err := s.Sets.GetSet(ctx, user, ids[0])
+	check(t, err)
+	set.Exercise, set.Weight, set.Reps = "Присед", 62.5, 6
+	check(t, s.Sets.UpdateSet(ctx, set))
+
+	updated, err := s.Sets.GetSet(ctx, user, ids[0])
+	check(t, err)
+	if updated.Exercise != "Присед" || updated.Weight != 62.5 || updated.Reps != 6 {
+		t.Fatalf("GetSet after UpdateSet = %+v", updated)
+	}
+
+	// Another user's range removes nothing
+	deleted, err := s.Sets.DeleteSets(ctx, other, ids[0], ids[2])
+	check(t, err)
+	if deleted != 0 {
+		t.Fatalf("DeleteSets of another user removed %d sets", deleted)
+	}
+
+	deleted, err = s.Sets.DeleteSets(ctx, user, ids[1], ids[2])
+	check(t, err)
+	if deleted != 2 {
+		t.Fatalf("DeleteSets removed %d sets, want 2", deleted)
+	}
+
+	check(t, s.Sets.DeleteSet(ctx, ids[0]))
+	check(t, s.Sets.RenameExerciseSets(ctx, user, "Присед", "Приседания"))
+
+	sets, err = s.Sets.GetTrainingSets(ctx, user, training.Training_id)
+	check(t, err)
+	if len(sets) != 1 || sets[0].Set_id != open.Set_id || sets[0].Exercise != "Приседания" {
+		t.Fatalf("GetTrainingSets = %+v, want the renamed recorded set only", sets)
+	}
+}
+
+func testExercises(t *testing.T, s Storage) {
+
+	ctx := context.Background()
+	register(t, s, user, other)
+
+	check(t, s.Exercises.AddExercise(ctx, user, "Присед"))
+	check(t, s.Exercises.AddExercise(ctx, user, "Жим"))
+	check(t, s.Exercises.AddExercise(ctx, other, "Присед"))
+
+	// The same name typed in another case or with Latin look-alikes
+	if err := s.Exercises.AddExercise(ctx, user, "ПPИСЕД"); !errors.Is(err, domain.ErrExerciseExists) {
+		t.Fatalf("AddExercise of a duplicate: %v, want ErrExerciseExists", err)
+	}
+
+	exercises, err := s.Exercises.GetExercises(ctx, user)
+	check(t, err)
+	if len(exercises) != 2 {
+		t.Fatalf("GetExercises = %+v, want 2 exercises", exercises)
+	}
+	squat, bench := exercises[0], exercises[1]
+	if squat.Name != "Присед" || bench.Name != "Жим" {
+		t.Fatalf("GetExercises = %+v, want them in the order they were added", exercises)
+	}
+
+	if _, err := s.Exercises.GetExercise(ctx, other, squat.Exercise_id); !errors.Is(err, domain.ErrUnknownExercise) {
+		t.Fatalf("GetExercise of another user: %v, want ErrUnknownExercise", err)
+	}
+
+	if err := s.Exercises.RenameExercise(ctx, user, bench.Exercise_id, "присед"); !errors.Is(err, domain.ErrExerciseExists) {
+		t.Fatalf("RenameExercise to a taken name: %v, want ErrExerciseExists", err)
+	}
+	check(t, s.Exercises.RenameExercise(ctx, user, bench.Exercise_id, "Жим лежа"))
+
+	renamed, err := s.Exercises.GetExercise(ctx, user, bench.Exercise_id)
+	check(t, err)
+	if renamed.Name != "Жим лежа" {
+		t.Fatalf("Name after RenameExercise = %q", renamed.Name)
+	}
+
+	check(t, s.Exercises.SetExerciseFavorite(ctx, user, bench.Exercise_id, true))
+	favorites, err := s.Exercises.GetFavoriteExercises(ctx, user)
+	check(t, err)
+	if len(favorites) != 1 || favorites[0].Exercise_id != bench.Exercise_id || !favorites[0].Favorite {
+		t.Fatalf("GetFavoriteExercises = %+v", favorites)
+	}
+
+	check(t, s.Exercises.SetExerciseArchived(ctx, user, squat.Exercise_id, true))
+
+	count, err := s.Exercises.CountExercises(ctx, user)
+	check(t, err)
+	archived, err := s.Exercises.GetArchivedExercises(ctx, user)
+	check(t, err)
+	if count != 1 || len(archived) != 1 || archived[0].Exercise_id != squat.Exercise_id || !archived[0].Archived {
+		t.Fatalf("after archiving CountExercises = %d, GetArchivedExercises = %+v", count, archived)
+	}
+
+	check(t, s.Exercises.DeleteExercise(ctx, user, squat.Exercise_id))
+	if _, err := s.Exercises.GetExercise(ctx, user, squat.Exercise_id); !errors.Is(err, domain.ErrUnknownExercise) {
+		t.Fatalf("GetExercise after DeleteExercise: %v, want ErrUnknownExercise", err)
+	}
+
+	otherExercises, err := s.Exercises.GetExercises(ctx, other)
+	check(t, err)
+	if len(otherExercises) != 1 {
+		t.Fatalf("another user's exercises changed: %+v", otherExercises)
+	}
+}
+
+func testExercisePages(t *testing.T, s Storage) {
+
+	ctx := context.Background()
+	register(t, s, user)
+
+	for _, name := range []string{"Тяга", "Жим", "Присед", "Армейский жим"} {
+		check(t, s.Exercises.AddExercise(ctx, user, name))
+	}
+
+	// Присед is done in the latest training, Жим twice in an earlier one
+	_, err := s.Trainings.AddTraining(ctx, domain.Training{User_id: user, Start: base, End: base.Add(time.Hour)})
+	check(t, err)
+	earlier, err := s.Trainings.GetTrainings(ctx, user)
+	check(t, err)
+	addSets(t, s, earlier[0].Training_id, "Жим", 2)
+
+	check(t, s.Trainings.StartTrainig(ctx, user, base.Add(24*time.Hour)))
+	latest, err := s.Trainings.GetActiveTraining(ctx, user)
+	check(t, err)
+	addSets(t, s, latest.Training_id, "Присед", 1)
+
+	tests := []struct {
+		order         domain.ExerciseOrder
+		offset, limit int64
+		want          []string
+	}{
+		{domain.OrderAdded, 0, 3, []string{"Тяга", "Жим", "Присед"}},
+		{domain.OrderAdded, 3, 3, []string{"Армейский жим"}},
+		{domain.OrderAdded, 4, 3, nil},
+		{domain.OrderName, 0, 4, []string{"Армейский жим", "Жим", "Присед", "Тяга"}},
+		{domain.OrderMostUsed, 0, 2, []string{"Жим", "Присед"}},
+		{domain.OrderRecent, 0, 2, []string{"Присед", "Жим"}},
+	}
+
+	for _, tt := range tests {
+		page, err := s.Exercises.GetPage(ctx, user, tt.order, tt.offset, tt.limit)
+		check(t, err)
+		if got := names(page); !slices.Equal(got, tt.want) {
+			t.Errorf("GetPage(%s, %d, %d) = %q, want %q", tt.order, tt.offset, tt.limit, got, tt.want)
+		}
+	}
+
+	last, err := s.Exercises.GetLastTrainingExercises(ctx, user)
+	check(t, err)
+	if got := names(last); !slices.Equal(got, []string{"Присед"}) {
+		t.Errorf("GetLastTrainingExercises = %q, want [Присед]", got)
+	}
+}
+
+func testStats(t *testing.T, s Storage) {
+
+	ctx := context.Background()
+	register(t, s, user)
+
+	// A finished training with 3 sets of Присед and 2 of Жим, then the active one with 1 set of Присед
+	// and Тяга chosen but not recorded, which counts nowhere
+	id, err := s.Trainings.AddTraining(ctx, domain.Training{User_id: user, Start: base, End: base.Add(time.Hour)})
+	check(t, err)
+	addSets(t, s, id, "Присед", 3)
+	addSets(t, s, id, "Жим", 2)
+
+	check(t, s.Trainings.StartTrainig(ctx, user, base.Add(24*time.Hour)))
+	active, err := s.Trainings.GetActiveTraining(ctx, user)
+	check(t, err)
+	addSets(t, s, active.Training_id, "Присед", 1)
+	check(t, s.Sets.SetExercise(ctx, user, "Тяга"))
+
+	most, err := s.Stats.GetMostPopularExercise(ctx, user)
+	check(t, err)
+	least, err := s.Stats.GetLeastPopularExercise(ctx, user)
+	check(t, err)
+	if most != "Присед" || least != "Жим" {
+		t.Errorf("most popular = %q, least popular = %q", most, least)
+	}
+
+	length, err := s.Stats.GetAverageTrainingsLenght(ctx, user)
+	check(t, err)
+	if length != time.Hour {
+		t.Errorf("GetAverageTrainingsLenght = %v, want 1h, the active training does not count", length)
+	}
+
+	for exercise, want := range map[string]int64{"Присед": 4, "Тяга": 0} {
+		total, err := s.Stats.GetTotalSetsPerExercise(ctx, user, exercise)
+		check(t, err)
+		if total != want {
+			t.Errorf("GetTotalSetsPerExercise(%s) = %d, want %d", exercise, total, want)
+		}
+	}
+
+	perTraining, err := s.Stats.GetAverageSetsPerTraining(ctx, user)
+	check(t, err)
+	if perTraining != 3 {
+		t.Errorf("GetAverageSetsPerTraining = %v, want 3", perTraining)
+	}
+
+	exercises, err := s.Stats.GetAverageExercisesPerTraining(ctx, user)
+	check(t, err)
+	if exercises != 1.5 {
+		t.Errorf("GetAverageExercisesPerTraining = %v, want 1.5", exercises)
+	}
+
+	for exercise, want := range map[string]string{"Присед": "2.00", "Тяга": "0.00"} {
+		perExercise, err := s.Stats.GetAverageSetsPerExerise(ctx, user, exercise)
+		check(t, err)
+		if perExercise != want {
+			t.Errorf("GetAverageSetsPerExerise(%s) = %q, want %s", exercise, perExercise, want)
+		}
+	}
+
+	weight, err := s.Stats.GetAverageWeight(ctx, user, "Присед")
+	check(t, err)
+	reps, err := s.Stats.GetAverageReps(ctx, user, "Присед")
+	check(t, err)
+	if weight != 100 || reps != "5.0" {
+		t.Errorf("GetAverageWeight = %v, GetAverageReps = %q", weight, reps)
+	}
+
+	for exercise, want := range map[string]int{"Присед": 1, "Тяга": 0} {
+		count, err := s.Stats.GetSetsCount(ctx, active, exercise)
+		check(t, err)
+		if count != want {
+			t.Errorf("GetSetsCount(%s) = %d, want %d", exercise, count, want)
+		}
+	}
+}
+
+// testEmptyStats checks a user who has not trained yet gets zero values, not errors.
+func testEmptyStats(t *testing.T, s Storage) {
+
+	ctx := context.Background()
+	register(t, s, user)
+
+	most, err := s.Stats.GetMostPopularExercise(ctx, user)
+	check(t, err)
+	least, err := s.Stats.GetLeastPopularExercise(ctx, user)
+	check(t, err)
+	if most != "" || least != "" {
+		t.Errorf("most popular = %q, least popular = %q, want empty", most, least)
+	}
+
+	weight, err := s.Stats.GetAverageWeight(ctx, user, "Присед")
+	check(t, err)
+	reps, err := s.Stats.GetAverageReps(ctx, user, "Присед")
+	check(t, err)
+	length, err := s.Stats.GetAverageTrainingsLenght(ctx, user)
+	check(t, err)
+	perTraining, err := s.Stats.GetAverageSetsPerTraining(ctx, user)
+	check(t, err)
+	perExercise, err := s.Stats.GetAverageSetsPerExerise(ctx, user, "Присед")
+	check(t, err)
+	if weight != 0 || reps != "0" || length != 0 || perTraining != 0 || perExercise != "0.00" {
+		t.Errorf("weight = %v, reps = %q, length = %v, sets per training = %v, sets per exercise = %q",
+			weight, reps, length, perTraining, perExercise)
+	}
+}
+
+func testDialogs(t *testing.T, s Storage) {
+
+	ctx := context.Background()
+	register(t, s, user)
+
+	dialog, err := s.Dialogs.GetDialog(ctx, user)
+	check(t, err)
+	if dialog != nil {
+		t.Fatalf("GetDialog without a dialog = %+v, want nil", dialog)
+	}
+
+	check(t, s.Dialogs.SaveDialog(ctx, domain.Dialog{
+		User_id:   user,
+		State:     "awaiting_weight",
+		Data:      map[string]string{"exercise": "7"},
+		ExpiresAt: base,
+	}))
+	check(t, s.Dialogs.SaveDialog(ctx, domain.Dialog{
+		User_id:   user,
+		State:     "awaiting_reps",
+		Data:      map[string]string{"weight": "100"},
+		ExpiresAt: base.Add(time.Minute),
+	}))
+
+	dialog, err = s.Dialogs.GetDialog(ctx, user)
+	check(t, err)
+	if dialog == nil || dialog.State != "awaiting_reps" || len(dialog.Data) != 1 || dialog.Data["weight"] != "100" ||
+		!dialog.ExpiresAt.Equal(base.Add(time.Minute)) {
+		t.Fatalf("GetDialog = %+v, want the last saved dialog", dialog)
+	}
+
+	check(t, s.Dialogs.DeleteDialog(ctx, user))
+
+	dialog, err = s.Dialogs.GetDialog(ctx, user)
+	check(t, err)
+	if dialog != nil {
+		t.Fatalf("GetDialog after DeleteDialog = %+v, want nil", dialog)
+	}
+}
+
+func testTx(t *testing.T, s Storage) {
+
+	ctx := context.Background()
+	register(t, s, user)
+
+	errRollback := errors.New("rollback")
+	err := s.Tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.Trainings.StartTrainig(ctx, user, base); err != nil {
+			return err
+		}
+		if err := s.Exercises.AddExercise(ctx, user, "Присед"); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("Do = %v, want the error of fn", err)
+	}
+
+	active, err := s.Trainings.IsTrainingActive(ctx, user)
+	check(t, err)
+	count, err := s.Exercises.CountExercises(ctx, user)
+	check(t, err)
+	if active || count != 0 {
+		t.Fatalf("after a rollback training active = %v, exercises = %d", active, count)
+	}
+
+	err = s.Tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.Trainings.StartTrainig(ctx, user, base); err != nil {
+			return err
+		}
+		return s.Exercises.AddExercise(ctx, user, "Присед")
+	})
+	check(t, err)
+
+	active, err = s.Trainings.IsTrainingActive(ctx, user)
+	check(t, err)
+	count, err = s.Exercises.CountExercises(ctx, user)
+	check(t, err)
+	if !active || count != 1 {
+		t.Fatalf("after a commit training active = %v, exercises = %d", active, count)
+	}
+}
+
+func register(t *testing.T, s Storage, ids ...int64) {
+	t.Helper()
+
+	for _, id := range ids {
+		check(t, s.Users.RegisterUser(context.Background(), id))
+	}
+}
+
+// addSets adds count recorded sets of 100 kg × 5 to the user's training.
+func addSets(t *testing.T, s Storage, trainingID int64, exercise string, count int) {
+	t.Helper()
+
+	for i := 0; i < count; i++ {
+		_, err := s.Sets.AddSet(context.Background(), domain.Set{
+			User_id:     user,
+			Training_id: trainingID,
+			Exercise:    exercise,
+			Weight:      100,
+			Reps:        5,
+		})
+		check(t, err)
+	}
+}
+
+func names(exercises []domain.Exercise) []string {
+
+	var names []string
+	for _, e := range exercises {
+		names = append(names, e.Name)
+	}
+
+	return names
+}
+
+func check(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
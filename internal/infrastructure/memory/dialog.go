@@ -0,0 +1,47 @@
+package memory
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"context"
+)
+
+func (s *Storage) GetDialog(ctx context.Context, id int64) (*domain.Dialog, error) {
+	defer s.lock(ctx)()
+
+	dialog, ok := s.st.dialogs[id]
+	if !ok {
+		return nil, nil
+	}
+
+	dialog = cloneDialog(dialog)
+
+	return &dialog, nil
+}
+
+func (s *Storage) SaveDialog(ctx context.Context, dialog domain.Dialog) error {
+	defer s.lock(ctx)()
+
+	s.st.dialogs[dialog.User_id] = cloneDialog(dialog)
+
+	return nil
+}
+
+func (s *Storage) DeleteDialog(ctx context.Context, id int64) error {
+	defer s.lock(ctx)()
+
+	delete(s.st.dialogs, id)
+
+	return nil
+}
+
+// cloneDialog copies the dialog data so callers never share the stored map.
+func cloneDialog(dialog domain.Dialog) domain.Dialog {
+
+	data := make(map[string]string, len(dialog.Data))
+	for k, v := range dialog.Data {
+		data[k] = v
+	}
+	dialog.Data = data
+
+	return dialog
+}
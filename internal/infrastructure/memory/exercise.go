@@ -0,0 +1,285 @@
+package memory
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+func (s *Storage) AddExercise(ctx context.Context, id int64, exercise string) error {
+	defer s.lock(ctx)()
+
+	if s.exerciseByKey(id, domain.ExerciseNameKey(exercise)) != nil {
+		return domain.ErrExerciseExists
+	}
+
+	s.st.lastExerciseID++
+	s.st.exercises = append(s.st.exercises, exerciseRecord{
+		id:     s.st.lastExerciseID,
+		userID: id,
+		name:   exercise,
+	})
+
+	return nil
+}
+
+// GetExercise returns the exercise only if it belongs to the user, otherwise ErrUnknownExercise.
+func (s *Storage) GetExercise(ctx context.Context, id, exerciseID int64) (domain.Exercise, error) {
+	defer s.lock(ctx)()
+
+	e := s.exercise(id, exerciseID)
+	if e == nil {
+		return domain.Exercise{}, domain.ErrUnknownExercise
+	}
+
+	return e.toDomain(), nil
+}
+
+func (s *Storage) GetExercises(ctx context.Context, id int64) ([]domain.Exercise, error) {
+	defer s.lock(ctx)()
+
+	var exercises []domain.Exercise
+	for _, e := range s.st.exercises {
+		if e.userID == id && !e.archived {
+			exercises = append(exercises, e.toDomain())
+		}
+	}
+
+	return exercises, nil
+}
+
+func (s *Storage) CountExercises(ctx context.Context, id int64) (int64, error) {
+	defer s.lock(ctx)()
+
+	var count int64
+	for _, e := range s.st.exercises {
+		if e.userID == id && !e.archived {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (s *Storage) GetPage(ctx context.Context, id int64, order domain.ExerciseOrder, offset, limit int64) ([]domain.Exercise, error) {
+	defer s.lock(ctx)()
+
+	var exercises []domain.Exercise
+	for _, e := range s.st.exercises {
+		if e.userID == id && !e.archived {
+			exercises = append(exercises, e.toDomain())
+		}
+	}
+
+	// exercises are kept in the order they were added, which is exercise_id order, so a stable
+	// sort breaks ties by exercise_id like the databases do
+	switch order {
+	case domain.OrderName:
+		sort.SliceStable(exercises, func(i, j int) bool {
+			return strings.ToLower(exercises[i].Name) < strings.ToLower(exercises[j].Name)
+		})
+	case domain.OrderMostUsed:
+		used := s.recordedSets(id)
+		sort.SliceStable(exercises, func(i, j int) bool {
+			return used[exercises[i].Name] > used[exercises[j].Name]
+		})
+	case domain.OrderRecent:
+		lastDone := s.lastDone(id)
+		sort.SliceStable(exercises, func(i, j int) bool {
+			return lastDone[exercises[i].Name].After(lastDone[exercises[j].Name])
+		})
+	}
+
+	offset = min(offset, int64(len(exercises)))
+
+	return exercises[offset:min(offset+limit, int64(len(exercises)))], nil
+}
+
+func (s *Storage) GetArchivedExercises(ctx context.Context, id int64) ([]domain.Exercise, error) {
+	defer s.lock(ctx)()
+
+	var exercises []domain.Exercise
+	for _, e := range s.st.exercises {
+		if e.userID == id && e.archived {
+			exercises = append(exercises, e.toDomain())
+		}
+	}
+
+	sort.Slice(exercises, func(i, j int) bool {
+		return exercises[i].Name < exercises[j].Name
+	})
+
+	return exercises, nil
+}
+
+func (s *Storage) GetFavoriteExercises(ctx context.Context, id int64) ([]domain.Exercise, error) {
+	defer s.lock(ctx)()
+
+	var exercises []domain.Exercise
+	for _, e := range s.st.exercises {
+		if e.userID == id && !e.archived && e.favorite {
+			exercises = append(exercises, e.toDomain())
+		}
+	}
+
+	sort.SliceStable(exercises, func(i, j int) bool {
+		return strings.ToLower(exercises[i].Name) < strings.ToLower(exercises[j].Name)
+	})
+
+	return exercises, nil
+}
+
+func (s *Storage) GetLastTrainingExercises(ctx context.Context, id int64) ([]domain.Exercise, error) {
+	defer s.lock(ctx)()
+
+	trainings := s.userTrainings(id)
+	if len(trainings) == 0 {
+		return nil, nil
+	}
+	last := trainings[len(trainings)-1].Training_id
+
+	// sets are kept in set_id order, so the first set of an exercise is the one it was first done with
+	var exercises []domain.Exercise
+	seen := make(map[string]bool)
+	for _, set := range s.st.sets {
+		if set.Training_id != last || seen[set.Exercise] {
+			continue
+		}
+		seen[set.Exercise] = true
+
+		if e := s.exerciseByName(id, set.Exercise); e != nil && !e.archived {
+			exercises = append(exercises, e.toDomain())
+		}
+	}
+
+	return exercises, nil
+}
+
+func (s *Storage) RenameExercise(ctx context.Context, id, exerciseID int64, newName string) error {
+	defer s.lock(ctx)()
+
+	if e := s.exerciseByKey(id, domain.ExerciseNameKey(newName)); e != nil && e.id != exerciseID {
+		return domain.ErrExerciseExists
+	}
+
+	if e := s.exercise(id, exerciseID); e != nil {
+		e.name = newName
+	}
+
+	return nil
+}
+
+func (s *Storage) SetExerciseArchived(ctx context.Context, id, exerciseID int64, archived bool) error {
+	defer s.lock(ctx)()
+
+	if e := s.exercise(id, exerciseID); e != nil {
+		e.archived = archived
+	}
+
+	return nil
+}
+
+func (s *Storage) SetExerciseFavorite(ctx context.Context, id, exerciseID int64, favorite bool) error {
+	defer s.lock(ctx)()
+
+	if e := s.exercise(id, exerciseID); e != nil {
+		e.favorite = favorite
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteExercise(ctx context.Context, id, exerciseID int64) error {
+	defer s.lock(ctx)()
+
+	exercises := s.st.exercises[:0]
+	for _, e := range s.st.exercises {
+		if e.userID != id || e.id != exerciseID {
+			exercises = append(exercises, e)
+		}
+	}
+	s.st.exercises = exercises
+
+	return nil
+}
+
+// exercise returns the user's exercise with the given id.
+func (s *Storage) exercise(id, exerciseID int64) *exerciseRecord {
+
+	for i := range s.st.exercises {
+		e := &s.st.exercises[i]
+		if e.userID == id && e.id == exerciseID {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) exerciseByName(id int64, name string) *exerciseRecord {
+
+	for i := range s.st.exercises {
+		e := &s.st.exercises[i]
+		if e.userID == id && e.name == name {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// recordedSets counts the user's recorded sets per exercise name.
+func (s *Storage) recordedSets(id int64) map[string]int {
+
+	used := make(map[string]int)
+	for _, set := range s.st.sets {
+		if set.User_id == id && set.recorded {
+			used[set.Exercise]++
+		}
+	}
+
+	return used
+}
+
+// lastDone returns the start of the latest training every exercise of the user was done in.
+func (s *Storage) lastDone(id int64) map[string]time.Time {
+
+	starts := make(map[int64]time.Time)
+	for _, t := range s.st.trainings {
+		starts[t.Training_id] = t.Start
+	}
+
+	lastDone := make(map[string]time.Time)
+	for _, set := range s.st.sets {
+		if start := starts[set.Training_id]; set.User_id == id && start.After(lastDone[set.Exercise]) {
+			lastDone[set.Exercise] = start
+		}
+	}
+
+	return lastDone
+}
+
+// exerciseByKey returns the user's exercise whose name has the given domain.ExerciseNameKey.
+func (s *Storage) exerciseByKey(id int64, key string) *exerciseRecord {
+
+	for i := range s.st.exercises {
+		e := &s.st.exercises[i]
+		if e.userID == id && domain.ExerciseNameKey(e.name) == key {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func (e exerciseRecord) toDomain() domain.Exercise {
+	return domain.Exercise{
+		Exercise_id: e.id,
+		User_id:     e.userID,
+		Name:        e.name,
+		Archived:    e.archived,
+		Favorite:    e.favorite,
+	}
+}
@@ -0,0 +1,192 @@
+package memory
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"context"
+	"time"
+)
+
+func (s *Storage) SetExercise(ctx context.Context, id int64, exercise string) error {
+	defer s.lock(ctx)()
+
+	if s.openSet(id) != nil {
+		return domain.ErrSetAlreadyOpen
+	}
+
+	training := s.activeTraining(id)
+	if training == nil {
+		return domain.ErrNoActiveTraining
+	}
+
+	s.st.lastSetID++
+	s.st.sets = append(s.st.sets, setRecord{
+		Set: domain.Set{
+			Set_id:      s.st.lastSetID,
+			User_id:     id,
+			Training_id: training.Training_id,
+			Exercise:    exercise,
+		},
+	})
+
+	return nil
+}
+
+func (s *Storage) StartSet(ctx context.Context, id int64, startTime time.Time) error {
+	defer s.lock(ctx)()
+
+	if set := s.openSet(id); set != nil && set.Start.IsZero() {
+		set.Start = startTime
+	}
+
+	return nil
+}
+
+func (s *Storage) IsExerciseChoosen(ctx context.Context, id int64) (bool, error) {
+	defer s.lock(ctx)()
+
+	return s.openSet(id) != nil, nil
+}
+
+func (s *Storage) IsSetStarted(ctx context.Context, id int64) (bool, error) {
+	defer s.lock(ctx)()
+
+	set := s.openSet(id)
+
+	return set != nil && !set.Start.IsZero(), nil
+}
+
+// LockOpenSet returns the user's unfinished set. Inside Do the storage is already held exclusively,
+// so there is nothing else to lock.
+func (s *Storage) LockOpenSet(ctx context.Context, id int64) (domain.Set, error) {
+	defer s.lock(ctx)()
+
+	set := s.openSet(id)
+	if set == nil {
+		return domain.Set{}, domain.ErrNoOpenSet
+	}
+
+	return set.Set, nil
+}
+
+func (s *Storage) FinishSet(ctx context.Context, setID int64, endTime time.Time, weight float64, reps int) error {
+	defer s.lock(ctx)()
+
+	for i := range s.st.sets {
+		set := &s.st.sets[i]
+		if set.Set_id == setID {
+			set.End = endTime
+			set.Weight = weight
+			set.Reps = reps
+			set.recorded = true
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) AddSet(ctx context.Context, set domain.Set) (int64, error) {
+	defer s.lock(ctx)()
+
+	s.st.lastSetID++
+	set.Set_id = s.st.lastSetID
+	s.st.sets = append(s.st.sets, setRecord{Set: set, recorded: true})
+
+	return set.Set_id, nil
+}
+
+func (s *Storage) GetSet(ctx context.Context, id, setID int64) (domain.Set, error) {
+	defer s.lock(ctx)()
+
+	for _, set := range s.st.sets {
+		if set.User_id == id && set.Set_id == setID && set.recorded {
+			return set.Set, nil
+		}
+	}
+
+	return domain.Set{}, domain.ErrUnknownSet
+}
+
+func (s *Storage) GetTrainingSets(ctx context.Context, id, trainingID int64) ([]domain.Set, error) {
+	defer s.lock(ctx)()
+
+	var sets []domain.Set
+	for _, set := range s.st.sets {
+		if set.User_id == id && set.Training_id == trainingID && set.recorded {
+			sets = append(sets, set.Set)
+		}
+	}
+
+	return sets, nil
+}
+
+func (s *Storage) UpdateSet(ctx context.Context, set domain.Set) error {
+	defer s.lock(ctx)()
+
+	for i := range s.st.sets {
+		stored := &s.st.sets[i]
+		if stored.User_id == set.User_id && stored.Set_id == set.Set_id {
+			stored.Exercise = set.Exercise
+			stored.Weight = set.Weight
+			stored.Reps = set.Reps
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteSet(ctx context.Context, setID int64) error {
+	defer s.lock(ctx)()
+
+	sets := s.st.sets[:0]
+	for _, set := range s.st.sets {
+		if set.Set_id != setID {
+			sets = append(sets, set)
+		}
+	}
+	s.st.sets = sets
+
+	return nil
+}
+
+func (s *Storage) DeleteSets(ctx context.Context, id, fromSetID, toSetID int64) (int64, error) {
+	defer s.lock(ctx)()
+
+	var deleted int64
+	sets := s.st.sets[:0]
+	for _, set := range s.st.sets {
+		if set.User_id == id && set.recorded && set.Set_id >= fromSetID && set.Set_id <= toSetID {
+			deleted++
+			continue
+		}
+		sets = append(sets, set)
+	}
+	s.st.sets = sets
+
+	return deleted, nil
+}
+
+func (s *Storage) RenameExerciseSets(ctx context.Context, id int64, exercise, newName string) error {
+	defer s.lock(ctx)()
+
+	for i := range s.st.sets {
+		set := &s.st.sets[i]
+		if set.User_id == id && set.Exercise == exercise {
+			set.Exercise = newName
+		}
+	}
+
+	return nil
+}
+
+// openSet returns the user's set that has an exercise chosen but is not recorded yet.
+func (s *Storage) openSet(id int64) *setRecord {
+
+	for i := range s.st.sets {
+		set := &s.st.sets[i]
+		if set.User_id == id && !set.recorded {
+			return set
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package memory
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"context"
+	"fmt"
+	"time"
+)
+
+func (s *Storage) GetMostPopularExercise(ctx context.Context, id int64) (string, error) {
+	defer s.lock(ctx)()
+
+	return s.popularExercise(id, func(count, best int) bool { return count > best }), nil
+}
+
+func (s *Storage) GetLeastPopularExercise(ctx context.Context, id int64) (string, error) {
+	defer s.lock(ctx)()
+
+	return s.popularExercise(id, func(count, best int) bool { return count < best }), nil
+}
+
+func (s *Storage) GetAverageWeight(ctx context.Context, id int64, exercise string) (float64, error) {
+	defer s.lock(ctx)()
+
+	var (
+		total float64
+		count int
+	)
+	for _, set := range s.st.sets {
+		if set.User_id == id && set.Exercise == exercise && set.recorded {
+			total += set.Weight
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+
+	return total / float64(count), nil
+}
+
+func (s *Storage) GetAverageReps(ctx context.Context, id int64, exercise string) (string, error) {
+	defer s.lock(ctx)()
+
+	var total, count int
+	for _, set := range s.st.sets {
+		if set.User_id == id && set.Exercise == exercise && set.recorded {
+			total += set.Reps
+			count++
+		}
+	}
+
+	if count == 0 {
+		return "0", nil
+	}
+
+	return fmt.Sprintf("%.1f", float64(total)/float64(count)), nil
+}
+
+func (s *Storage) GetAverageTrainingsLenght(ctx context.Context, id int64) (time.Duration, error) {
+	defer s.lock(ctx)()
+
+	var (
+		total time.Duration
+		count int
+	)
+	for _, t := range s.st.trainings {
+		if t.User_id == id && !t.End.IsZero() {
+			total += t.End.Sub(t.Start)
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+
+	return total / time.Duration(count), nil
+}
+
+func (s *Storage) GetTrainingsCount(ctx context.Context, id int64) (int64, error) {
+	defer s.lock(ctx)()
+
+	return int64(len(s.userTrainings(id))), nil
+}
+
+func (s *Storage) GetTotalSetsPerExercise(ctx context.Context, id int64, exercise string) (int64, error) {
+	defer s.lock(ctx)()
+
+	var count int64
+	for _, set := range s.st.sets {
+		if set.User_id == id && set.Exercise == exercise && set.recorded {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (s *Storage) GetSetsCount(ctx context.Context, training domain.Training, exercise string) (int, error) {
+	defer s.lock(ctx)()
+
+	var count int
+	for _, set := range s.st.sets {
+		if set.Training_id == training.Training_id && set.Exercise == exercise && set.recorded {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (s *Storage) GetAverageSetsPerExerise(ctx context.Context, id int64, exercise string) (string, error) {
+	defer s.lock(ctx)()
+
+	avg := s.averageOver(id, func(sets []setRecord) float64 {
+		var count int
+		for _, set := range sets {
+			if set.Exercise == exercise {
+				count++
+			}
+		}
+		return float64(count)
+	})
+
+	return fmt.Sprintf("%.2f", avg), nil
+}
+
+func (s *Storage) GetAverageExercisesPerTraining(ctx context.Context, id int64) (float64, error) {
+	defer s.lock(ctx)()
+
+	return s.averageOver(id, func(sets []setRecord) float64 {
+		exercises := make(map[string]struct{})
+		for _, set := range sets {
+			exercises[set.Exercise] = struct{}{}
+		}
+		return float64(len(exercises))
+	}), nil
+}
+
+func (s *Storage) GetAverageSetsPerTraining(ctx context.Context, id int64) (float64, error) {
+	defer s.lock(ctx)()
+
+	return s.averageOver(id, func(sets []setRecord) float64 {
+		return float64(len(sets))
+	}), nil
+}
+
+// averageOver returns the average of perTraining over all trainings of the user, 0 when there are none.
+// perTraining gets the recorded sets of a training; like the Postgres LEFT JOIN, a training without
+// them counts with an empty slice.
+func (s *Storage) averageOver(id int64, perTraining func(sets []setRecord) float64) float64 {
+
+	trainings := s.userTrainings(id)
+	if len(trainings) == 0 {
+		return 0
+	}
+
+	byTraining := make(map[int64][]setRecord)
+	for _, set := range s.st.sets {
+		if set.recorded {
+			byTraining[set.Training_id] = append(byTraining[set.Training_id], set)
+		}
+	}
+
+	var total float64
+	for _, t := range trainings {
+		total += perTraining(byTraining[t.Training_id])
+	}
+
+	return total / float64(len(trainings))
+}
+
+// popularExercise returns the exercise whose recorded set count wins against all others by better,
+// or an empty string when the user has no recorded sets.
+func (s *Storage) popularExercise(id int64, better func(count, best int) bool) string {
+
+	var (
+		order  []string
+		counts = make(map[string]int)
+	)
+	for _, set := range s.st.sets {
+		if set.User_id != id || !set.recorded {
+			continue
+		}
+		if _, ok := counts[set.Exercise]; !ok {
+			order = append(order, set.Exercise)
+		}
+		counts[set.Exercise]++
+	}
+
+	var best string
+	for i, exercise := range order {
+		if i == 0 || better(counts[exercise], counts[best]) {
+			best = exercise
+		}
+	}
+
+	return best
+}
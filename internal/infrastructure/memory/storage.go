@@ -0,0 +1,109 @@
+package memory
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"context"
+	"sync"
+)
+
+type exerciseRecord struct {
+	id       int64
+	userID   int64
+	name     string
+	archived bool
+	favorite bool
+}
+
+type setRecord struct {
+	domain.Set
+	recorded bool // weight and reps are filled in, a set without them is the open one
+}
+
+type state struct {
+	users     map[int64]struct{}
+	exercises []exerciseRecord
+	trainings []domain.Training
+	sets      []setRecord
+	dialogs   map[int64]domain.Dialog
+
+	lastExerciseID int64
+	lastTrainingID int64
+	lastSetID      int64
+}
+
+func (st *state) clone() state {
+
+	c := *st
+
+	c.users = make(map[int64]struct{}, len(st.users))
+	for k, v := range st.users {
+		c.users[k] = v
+	}
+
+	c.exercises = append([]exerciseRecord(nil), st.exercises...)
+	c.trainings = append([]domain.Training(nil), st.trainings...)
+	c.sets = append([]setRecord(nil), st.sets...)
+
+	c.dialogs = make(map[int64]domain.Dialog, len(st.dialogs))
+	for k, v := range st.dialogs {
+		c.dialogs[k] = cloneDialog(v)
+	}
+
+	return c
+}
+
+type txKey struct{}
+
+// Storage keeps all the bot's data in memory and implements every repository interface together with
+// repository.UnitOfWork. It is meant for tests and local development: nothing survives a restart.
+//
+// All calls are serialized by a single mutex. Do holds it for the whole transaction, so a unit of work
+// is isolated from other callers and is rolled back to a snapshot if it fails.
+type Storage struct {
+	mu sync.Mutex
+	st state
+}
+
+func NewStorage() *Storage {
+	return &Storage{
+		st: state{
+			users:   make(map[int64]struct{}),
+			dialogs: make(map[int64]domain.Dialog),
+		},
+	}
+}
+
+func (s *Storage) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+
+	if s.inTx(ctx) {
+		return fn(ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := s.st.clone()
+
+	if err := fn(context.WithValue(ctx, txKey{}, s)); err != nil {
+		s.st = snapshot
+		return err
+	}
+
+	return nil
+}
+
+// lock takes the storage mutex unless the call is part of a transaction that already holds it.
+func (s *Storage) lock(ctx context.Context) func() {
+
+	if s.inTx(ctx) {
+		return func() {}
+	}
+
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+func (s *Storage) inTx(ctx context.Context) bool {
+	owner, ok := ctx.Value(txKey{}).(*Storage)
+	return ok && owner == s
+}
@@ -0,0 +1,24 @@
+package memory_test
+
+import (
+	"GymBot/internal/domain/repository/repositorytest"
+	"GymBot/internal/infrastructure/memory"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Storage {
+
+		s := memory.NewStorage()
+
+		return repositorytest.Storage{
+			Users:     s,
+			Trainings: s,
+			Sets:      s,
+			Exercises: s,
+			Stats:     s,
+			Dialogs:   s,
+			Tx:        s,
+		}
+	})
+}
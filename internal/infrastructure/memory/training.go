@@ -0,0 +1,110 @@
+package memory
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"context"
+	"sort"
+	"time"
+)
+
+func (s *Storage) StartTrainig(ctx context.Context, id int64, startTime time.Time) error {
+	defer s.lock(ctx)()
+
+	if s.activeTraining(id) != nil {
+		return domain.ErrTrainingAlreadyActive
+	}
+
+	s.st.lastTrainingID++
+	s.st.trainings = append(s.st.trainings, domain.Training{
+		Training_id: s.st.lastTrainingID,
+		User_id:     id,
+		Start:       startTime,
+	})
+
+	return nil
+}
+
+func (s *Storage) EndTraining(ctx context.Context, id int64, endTime time.Time) error {
+	defer s.lock(ctx)()
+
+	if t := s.activeTraining(id); t != nil {
+		t.End = endTime
+	}
+
+	return nil
+}
+
+func (s *Storage) IsTrainingActive(ctx context.Context, id int64) (bool, error) {
+	defer s.lock(ctx)()
+
+	return s.activeTraining(id) != nil, nil
+}
+
+func (s *Storage) GetActiveTraining(ctx context.Context, id int64) (domain.Training, error) {
+	defer s.lock(ctx)()
+
+	t := s.activeTraining(id)
+	if t == nil {
+		return domain.Training{}, domain.ErrNoActiveTraining
+	}
+
+	return *t, nil
+}
+
+func (s *Storage) GetTrainings(ctx context.Context, id int64) ([]domain.Training, error) {
+	defer s.lock(ctx)()
+
+	return s.userTrainings(id), nil
+}
+
+func (s *Storage) GetTraining(ctx context.Context, id, trainingID int64) (domain.Training, error) {
+	defer s.lock(ctx)()
+
+	for _, t := range s.st.trainings {
+		if t.User_id == id && t.Training_id == trainingID {
+			return t, nil
+		}
+	}
+
+	return domain.Training{}, domain.ErrUnknownTraining
+}
+
+func (s *Storage) AddTraining(ctx context.Context, training domain.Training) (int64, error) {
+	defer s.lock(ctx)()
+
+	s.st.lastTrainingID++
+	training.Training_id = s.st.lastTrainingID
+	s.st.trainings = append(s.st.trainings, training)
+
+	return training.Training_id, nil
+}
+
+// activeTraining returns the user's training that has not ended yet.
+func (s *Storage) activeTraining(id int64) *domain.Training {
+
+	for i := range s.st.trainings {
+		t := &s.st.trainings[i]
+		if t.User_id == id && t.End.IsZero() {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// userTrainings returns a copy of the user's trainings ordered by start time.
+func (s *Storage) userTrainings(id int64) []domain.Training {
+
+	var trainings []domain.Training
+	for _, t := range s.st.trainings {
+		if t.User_id == id {
+			trainings = append(trainings, t)
+		}
+	}
+
+	sort.SliceStable(trainings, func(i, j int) bool {
+		return trainings[i].Start.Before(trainings[j].Start)
+	})
+
+	return trainings
+}
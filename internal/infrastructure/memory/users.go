@@ -0,0 +1,19 @@
+package memory
+
+import "context"
+
+func (s *Storage) RegisterUser(ctx context.Context, id int64) error {
+	defer s.lock(ctx)()
+
+	s.st.users[id] = struct{}{}
+
+	return nil
+}
+
+func (s *Storage) UserCheck(ctx context.Context, id int64) (bool, error) {
+	defer s.lock(ctx)()
+
+	_, ok := s.st.users[id]
+
+	return ok, nil
+}
@@ -0,0 +1,52 @@
+package postgres_test
+
+import (
+	"GymBot/internal/domain/repository/repositorytest"
+	"GymBot/internal/infrastructure/postgres"
+	"context"
+	"os"
+	"testing"
+)
+
+// TestConformance needs a database it may wipe, set TEST_DB_CONNECTION to its connection string.
+func TestConformance(t *testing.T) {
+
+	connString := os.Getenv("TEST_DB_CONNECTION")
+	if connString == "" {
+		t.Skip("TEST_DB_CONNECTION is not set")
+	}
+
+	ctx := context.Background()
+
+	db, err := postgres.NewPool(ctx, connString, postgres.PoolConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	migrator, err := postgres.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Storage {
+
+		_, err := db.Exec(ctx, "TRUNCATE users, exercises, trainings, sets, dialogs RESTART IDENTITY CASCADE")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return repositorytest.Storage{
+			Users:     postgres.NewUserRepositoryDb(db),
+			Trainings: postgres.NewTrainingRepositoryDb(db),
+			Sets:      postgres.NewSetRepositoryDb(db),
+			Exercises: postgres.NewExerciseRepositoryDb(db),
+			Stats:     postgres.NewStatsRepositoryDb(db),
+			Dialogs:   postgres.NewDialogRepositoryDb(db),
+			Tx:        postgres.NewUnitOfWorkDb(db),
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"GymBot/internal/domain/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DialogRepositoryDB struct {
+	Db *pgxpool.Pool
+}
+
+func NewDialogRepositoryDb(db *pgxpool.Pool) repository.DialogRepository {
+	return &DialogRepositoryDB{
+		Db: db,
+	}
+}
+
+func (d *DialogRepositoryDB) GetDialog(ctx context.Context, id int64) (*domain.Dialog, error) {
+
+	q := squirrel.Select("user_id", "state", "data", "expires_at").From("dialogs").Where(
+		squirrel.Eq{"user_id": id}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("GetDialog ToSql Error:", slog.Any("err", err))
+		return nil, err
+	}
+
+	var (
+		dialog domain.Dialog
+		data   []byte
+	)
+
+	err = conn(ctx, d.Db).QueryRow(ctx, query, args...).Scan(&dialog.User_id, &dialog.State, &data, &dialog.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		slog.Error("GetDialog QueryRow Error:", slog.Any("err", err))
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &dialog.Data); err != nil {
+		slog.Error("GetDialog Unmarshal Error:", slog.Any("err", err))
+		return nil, err
+	}
+
+	return &dialog, nil
+}
+
+func (d *DialogRepositoryDB) SaveDialog(ctx context.Context, dialog domain.Dialog) error {
+
+	data, err := json.Marshal(dialog.Data)
+	if err != nil {
+		slog.Error("SaveDialog Marshal Error:", slog.Any("err", err))
+		return err
+	}
+
+	q := squirrel.Insert("dialogs").Columns("user_id", "state", "data", "expires_at").
+		Values(dialog.User_id, dialog.State, string(data), dialog.ExpiresAt).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET state = EXCLUDED.state, data = EXCLUDED.data, expires_at = EXCLUDED.expires_at").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("SaveDialog ToSql Error:", slog.Any("err", err))
+		return err
+	}
+
+	_, err = conn(ctx, d.Db).Exec(ctx, query, args...)
+	if err != nil {
+		slog.Error("SaveDialog Exec Error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+func (d *DialogRepositoryDB) DeleteDialog(ctx context.Context, id int64) error {
+
+	q := squirrel.Delete("dialogs").Where(squirrel.Eq{"user_id": id}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("DeleteDialog ToSql Error:", slog.Any("err", err))
+		return err
+	}
+
+	_, err = conn(ctx, d.Db).Exec(ctx, query, args...)
+	if err != nil {
+		slog.Error("DeleteDialog Exec Error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
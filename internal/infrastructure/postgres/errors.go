@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	uniqueViolation = "23505"
+	checkViolation  = "23514"
+)
+
+// constraintViolated reports whether err was caused by the named constraint or index failing with the given code.
+func constraintViolated(err error, code, constraint string) bool {
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == code && pgErr.ConstraintName == constraint
+}
@@ -0,0 +1,278 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+var ErrNoMigrationToRollback = errors.New("no applied migrations to roll back")
+
+// Migration is a single schema change shipped with the binary as a pair of
+// NNNN_name.up.sql / NNNN_name.down.sql files. A "-- +go name" line runs the Go step
+// registered in goSteps at that point.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	Db         *pgxpool.Pool
+	Migrations []Migration
+}
+
+func NewMigrator(db *pgxpool.Pool) (*Migrator, error) {
+
+	migrations, err := loadMigrations(migrationsFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		Db:         db,
+		Migrations: migrations,
+	}, nil
+}
+
+// Up applies every pending migration in version order and returns the applied ones.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.Migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := m.inTx(ctx, func(tx pgx.Tx) error {
+			if err := execScript(ctx, tx, migration.Up); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name)
+			return err
+		})
+		if err != nil {
+			slog.Error("Migration up error:", slog.Int64("version", migration.Version), slog.Any("err", err))
+			return done, fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		slog.Info("Migration applied", slog.Int64("version", migration.Version), slog.String("name", migration.Name))
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down rolls back the latest applied migration.
+func (m *Migrator) Down(ctx context.Context) (Migration, error) {
+
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return Migration{}, err
+	}
+
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+
+	for i := len(m.Migrations) - 1; i >= 0; i-- {
+		migration := m.Migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err := m.inTx(ctx, func(tx pgx.Tx) error {
+			if err := execScript(ctx, tx, migration.Down); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+			return err
+		})
+		if err != nil {
+			slog.Error("Migration down error:", slog.Int64("version", migration.Version), slog.Any("err", err))
+			return Migration{}, fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		slog.Info("Migration rolled back", slog.Int64("version", migration.Version), slog.String("name", migration.Name))
+		return migration, nil
+	}
+
+	return Migration{}, ErrNoMigrationToRollback
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.Migrations))
+	for _, migration := range m.Migrations {
+		appliedAt, ok := applied[migration.Version]
+		statuses = append(statuses, MigrationStatus{
+			Migration: migration,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+
+	return statuses, nil
+}
+
+func (m *Migrator) ensureVersionTable(ctx context.Context) error {
+
+	_, err := m.Db.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    BIGINT PRIMARY KEY,
+		name       TEXT        NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		slog.Error("Create schema_migrations error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int64]time.Time, error) {
+
+	rows, err := m.Db.Query(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		slog.Error("Applied versions Query error:", slog.Any("err", err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var (
+			version   int64
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			slog.Error("Applied versions Scan error:", slog.Any("err", err))
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+func (m *Migrator) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+
+	tx, err := m.Db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		prefix, title, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %q: name must look like NNNN_title", name)
+		}
+
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %q: bad version: %w", name, err)
+		}
+
+		body, err := fs.ReadFile(fsys, dir+"/"+name)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkGoSteps(string(body)); err != nil {
+			return nil, fmt.Errorf("migration %q: %w", name, err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: title}
+			byVersion[version] = migration
+		}
+
+		if direction == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s: both up and down files are required", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
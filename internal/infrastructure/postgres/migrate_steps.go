@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// goStepPrefix starts a line of a migration file that runs a Go step at that point of the script.
+const goStepPrefix = "-- +go "
+
+// goSteps are the migration steps SQL can't express the way the application does. lower() and
+// \s depend on the database collation, so exercise names are normalized by the domain functions.
+var goSteps = map[string]func(ctx context.Context, tx pgx.Tx) error{
+	"normalize_exercise_names": normalizeExerciseNames,
+}
+
+// execScript runs the migration script, calling the Go steps it names in order with its SQL.
+func execScript(ctx context.Context, tx pgx.Tx, script string) error {
+
+	var sql strings.Builder
+	flush := func() error {
+		defer sql.Reset()
+		if strings.TrimSpace(sql.String()) == "" {
+			return nil
+		}
+		_, err := tx.Exec(ctx, sql.String())
+		return err
+	}
+
+	for _, line := range strings.SplitAfter(script, "\n") {
+		name, ok := goStep(line)
+		if !ok {
+			sql.WriteString(line)
+			continue
+		}
+
+		if err := flush(); err != nil {
+			return err
+		}
+		if err := goSteps[name](ctx, tx); err != nil {
+			return fmt.Errorf("go step %s: %w", name, err)
+		}
+	}
+
+	return flush()
+}
+
+// checkGoSteps reports a step the script names that does not exist.
+func checkGoSteps(script string) error {
+
+	for _, line := range strings.SplitAfter(script, "\n") {
+		if name, ok := goStep(line); ok && goSteps[name] == nil {
+			return fmt.Errorf("unknown go step %q", name)
+		}
+	}
+
+	return nil
+}
+
+func goStep(line string) (string, bool) {
+
+	name, ok := strings.CutPrefix(strings.TrimSpace(line), goStepPrefix)
+	if !ok {
+		return "", false
+	}
+
+	return strings.TrimSpace(name), true
+}
+
+// normalizeExerciseNames collapses whitespace in exercise names, in the exercises and their sets,
+// and fills name_key with domain.ExerciseNameKey.
+func normalizeExerciseNames(ctx context.Context, tx pgx.Tx) error {
+
+	rows, err := tx.Query(ctx, "SELECT DISTINCT exercise_name FROM sets")
+	if err != nil {
+		return err
+	}
+	setNames, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+
+	for _, name := range setNames {
+		if normalized := normalizeSpace(name); normalized != name {
+			if _, err := tx.Exec(ctx, "UPDATE sets SET exercise_name = $1 WHERE exercise_name = $2", normalized, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	type exercise struct {
+		id   int64
+		name string
+	}
+
+	rows, err = tx.Query(ctx, "SELECT exercise_id, name FROM exercises")
+	if err != nil {
+		return err
+	}
+	exercises, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (exercise, error) {
+		var e exercise
+		err := row.Scan(&e.id, &e.name)
+		return e, err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, e := range exercises {
+		name := normalizeSpace(e.name)
+		_, err := tx.Exec(ctx, "UPDATE exercises SET name = $1, name_key = $2 WHERE exercise_id = $3",
+			name, domain.ExerciseNameKey(name), e.id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeSpace trims the name and collapses inner whitespace like domain.NormalizeExerciseName.
+func normalizeSpace(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+
+	migrations, err := loadMigrations(migrationsFS, "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("migration %d has version %d, versions must have no gaps", i, migration.Version)
+		}
+	}
+}
+
+func TestLoadMigrationsUnknownGoStep(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"migrations/0001_init.up.sql":   {Data: []byte("CREATE TABLE t (id INT);\n-- +go no_such_step\n")},
+		"migrations/0001_init.down.sql": {Data: []byte("DROP TABLE t;\n")},
+	}
+
+	_, err := loadMigrations(fsys, "migrations")
+	if err == nil || !strings.Contains(err.Error(), "no_such_step") {
+		t.Fatalf("loadMigrations = %v, want an unknown go step error", err)
+	}
+}
+
+func TestGoStep(t *testing.T) {
+
+	tests := []struct {
+		line string
+		name string
+		ok   bool
+	}{
+		{"-- +go normalize_exercise_names\n", "normalize_exercise_names", true},
+		{"  -- +go normalize_exercise_names  ", "normalize_exercise_names", true},
+		{"-- normalize_exercise_names\n", "", false},
+		{"UPDATE sets SET recorded = TRUE;\n", "", false},
+	}
+
+	for _, tt := range tests {
+		name, ok := goStep(tt.line)
+		if name != tt.name || ok != tt.ok {
+			t.Errorf("goStep(%q) = %q, %v, want %q, %v", tt.line, name, ok, tt.name, tt.ok)
+		}
+	}
+}
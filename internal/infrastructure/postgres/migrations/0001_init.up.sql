@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS users (
+    user_id BIGINT PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS exercises (
+    exercise_id BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    name        TEXT   NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS exercises_user_id_idx ON exercises (user_id);
+
+CREATE TABLE IF NOT EXISTS trainings (
+    training_id BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT      NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    start_time  TIMESTAMPTZ NOT NULL,
+    end_time    TIMESTAMPTZ,
+    CONSTRAINT trainings_time_order CHECK (end_time IS NULL OR end_time >= start_time)
+);
+
+CREATE INDEX IF NOT EXISTS trainings_user_id_start_time_idx ON trainings (user_id, start_time);
+
+CREATE TABLE IF NOT EXISTS sets (
+    set_id        BIGSERIAL PRIMARY KEY,
+    user_id       BIGINT NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    exercise_name TEXT   NOT NULL,
+    start_time    TIMESTAMPTZ,
+    end_time      TIMESTAMPTZ,
+    weight        DOUBLE PRECISION,
+    reps          INTEGER,
+    CONSTRAINT sets_weight_non_negative CHECK (weight IS NULL OR weight >= 0),
+    CONSTRAINT sets_reps_non_negative CHECK (reps IS NULL OR reps >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS sets_user_id_exercise_name_idx ON sets (user_id, exercise_name);
+
+CREATE TABLE IF NOT EXISTS dialogs (
+    user_id    BIGINT PRIMARY KEY REFERENCES users (user_id) ON DELETE CASCADE,
+    state      TEXT        NOT NULL,
+    data       JSONB       NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS sets_training_id_idx;
+
+ALTER TABLE sets DROP COLUMN IF EXISTS training_id;
@@ -0,0 +1,18 @@
+ALTER TABLE sets ADD COLUMN IF NOT EXISTS training_id BIGINT REFERENCES trainings (training_id) ON DELETE SET NULL;
+
+-- Assign sets logged before this migration to the training whose time window contains them.
+-- When trainings overlap, the latest one started before the set wins.
+UPDATE sets s
+SET training_id = (
+    SELECT t.training_id
+    FROM trainings t
+    WHERE t.user_id = s.user_id
+      AND t.start_time <= s.start_time
+      AND (t.end_time IS NULL OR t.end_time >= COALESCE(s.end_time, s.start_time))
+    ORDER BY t.start_time DESC
+    LIMIT 1
+)
+WHERE s.training_id IS NULL
+  AND s.start_time IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS sets_training_id_idx ON sets (training_id);
@@ -0,0 +1,5 @@
+ALTER TABLE sets DROP CONSTRAINT IF EXISTS sets_training_required;
+
+DROP INDEX IF EXISTS sets_one_open_per_user_idx;
+
+DROP INDEX IF EXISTS trainings_one_active_per_user_idx;
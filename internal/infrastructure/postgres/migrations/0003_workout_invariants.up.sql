@@ -0,0 +1,51 @@
+-- Close all but the latest open training of every user.
+UPDATE trainings t
+SET end_time = t.start_time
+WHERE t.end_time IS NULL
+  AND EXISTS (
+    SELECT 1
+    FROM trainings newer
+    WHERE newer.user_id = t.user_id
+      AND newer.end_time IS NULL
+      AND newer.training_id > t.training_id
+);
+
+-- Drop all but the latest open set of every user.
+DELETE FROM sets s
+WHERE s.end_time IS NULL
+  AND EXISTS (
+    SELECT 1
+    FROM sets newer
+    WHERE newer.user_id = s.user_id
+      AND newer.end_time IS NULL
+      AND newer.set_id > s.set_id
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS trainings_one_active_per_user_idx ON trainings (user_id) WHERE end_time IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS sets_one_open_per_user_idx ON sets (user_id) WHERE end_time IS NULL;
+
+-- Sets logged before training_id existed may have no training. The unfinished ones can't be
+-- finished any more, so they are dropped.
+DELETE FROM sets WHERE training_id IS NULL AND end_time IS NULL;
+
+-- The finished ones get a training per user and day that spans them.
+INSERT INTO trainings (user_id, start_time, end_time)
+SELECT user_id, MIN(LEAST(COALESCE(start_time, end_time), end_time)), MAX(end_time)
+FROM sets
+WHERE training_id IS NULL
+GROUP BY user_id, date_trunc('day', end_time);
+
+UPDATE sets s
+SET training_id = (
+    SELECT t.training_id
+    FROM trainings t
+    WHERE t.user_id = s.user_id
+      AND t.start_time <= LEAST(COALESCE(s.start_time, s.end_time), s.end_time)
+      AND t.end_time >= s.end_time
+    ORDER BY t.start_time DESC
+    LIMIT 1
+)
+WHERE s.training_id IS NULL;
+
+ALTER TABLE sets ADD CONSTRAINT sets_training_required CHECK (training_id IS NOT NULL);
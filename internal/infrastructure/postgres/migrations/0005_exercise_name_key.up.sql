@@ -0,0 +1,34 @@
+-- name_key is the form exercise names are compared in, see domain.ExerciseNameKey:
+-- whitespace collapsed, lower case, Latin look-alikes replaced with Cyrillic letters.
+-- lower() folds only ASCII under some collations, so the names and keys are computed in Go.
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS name_key TEXT;
+
+-- +go normalize_exercise_names
+
+-- Merge exercises with the same key into the oldest one: move their sets, then drop them.
+UPDATE sets s
+SET exercise_name = keep.name
+FROM exercises dup
+JOIN exercises keep
+  ON keep.user_id = dup.user_id
+ AND keep.name_key = dup.name_key
+ AND keep.exercise_id < dup.exercise_id
+WHERE s.user_id = dup.user_id
+  AND s.exercise_name = dup.name
+  AND NOT EXISTS (
+    SELECT 1
+    FROM exercises older
+    WHERE older.user_id = keep.user_id
+      AND older.name_key = keep.name_key
+      AND older.exercise_id < keep.exercise_id
+);
+
+DELETE FROM exercises dup
+USING exercises keep
+WHERE keep.user_id = dup.user_id
+  AND keep.name_key = dup.name_key
+  AND keep.exercise_id < dup.exercise_id;
+
+ALTER TABLE exercises ALTER COLUMN name_key SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS exercises_user_id_name_key_idx ON exercises (user_id, name_key);
@@ -0,0 +1,2 @@
+-- Favourite exercises are pinned at the top of the picker.
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS favorite BOOLEAN NOT NULL DEFAULT FALSE;
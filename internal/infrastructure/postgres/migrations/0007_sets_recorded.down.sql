@@ -0,0 +1,15 @@
+-- Sets logged without the timers get the start of their training as the end time.
+UPDATE sets s
+SET end_time = t.start_time
+FROM trainings t
+WHERE t.training_id = s.training_id
+  AND s.recorded
+  AND s.end_time IS NULL;
+
+DELETE FROM sets WHERE recorded AND end_time IS NULL;
+
+DROP INDEX IF EXISTS sets_one_open_per_user_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS sets_one_open_per_user_idx ON sets (user_id) WHERE end_time IS NULL;
+
+ALTER TABLE sets DROP COLUMN IF EXISTS recorded;
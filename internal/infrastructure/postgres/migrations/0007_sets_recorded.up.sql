@@ -0,0 +1,12 @@
+-- A set is recorded once its weight and reps are entered. Sets logged without the timers have
+-- no start and end time, so the end time no longer tells whether a set is still open.
+ALTER TABLE sets ADD COLUMN IF NOT EXISTS recorded BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE sets SET recorded = TRUE WHERE end_time IS NOT NULL AND weight IS NOT NULL AND reps IS NOT NULL;
+
+-- Sets ended without their weight or reps were never finished, they can't be shown or counted.
+DELETE FROM sets WHERE end_time IS NOT NULL AND NOT recorded;
+
+DROP INDEX IF EXISTS sets_one_open_per_user_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS sets_one_open_per_user_idx ON sets (user_id) WHERE NOT recorded;
@@ -0,0 +1,405 @@
+package postgres
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"GymBot/internal/domain/repository"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// setColumns are the columns scanSet reads.
+var setColumns = []string{"set_id", "user_id", "training_id", "exercise_name", "weight", "reps", "start_time", "end_time"}
+
+type SetRepositoryDB struct {
+	Db *pgxpool.Pool
+}
+
+func NewSetRepositoryDb(db *pgxpool.Pool) repository.SetRepository {
+	return &SetRepositoryDB{
+		Db: db,
+	}
+}
+
+func (s *SetRepositoryDB) SetExercise(ctx context.Context, id int64, exercise string) error {
+
+	activeTraining := squirrel.Expr(
+		"(SELECT training_id FROM trainings WHERE user_id = ? AND end_time IS NULL ORDER BY start_time DESC LIMIT 1)", id)
+
+	q := squirrel.Insert("sets").Columns("exercise_name", "user_id", "training_id").
+		Values(exercise, id, activeTraining).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Set Exercise ToSql Error:", slog.Any("err", err))
+		return err
+	}
+
+	_, err = conn(ctx, s.Db).Exec(ctx, query, args...)
+	if constraintViolated(err, uniqueViolation, "sets_one_open_per_user_idx") {
+		return domain.ErrSetAlreadyOpen
+	}
+	if constraintViolated(err, checkViolation, "sets_training_required") {
+		return domain.ErrNoActiveTraining
+	}
+	if err != nil {
+		slog.Error("Set Exercise Exec Error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *SetRepositoryDB) StartSet(ctx context.Context, id int64, startTime time.Time) error {
+
+	q := squirrel.Update("sets").Set("start_time", startTime).Where(
+		squirrel.And{
+			squirrel.Eq{"user_id": id},
+			squirrel.Expr("start_time IS NULL AND exercise_name IS NOT NULL AND NOT recorded"),
+		}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Start Set ToSql Error:", slog.Any("err", err))
+		return err
+	}
+
+	_, err = conn(ctx, s.Db).Exec(ctx, query, args...)
+	if err != nil {
+		slog.Error("Start Set Exec Error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *SetRepositoryDB) IsExerciseChoosen(ctx context.Context, id int64) (bool, error) {
+
+	q := squirrel.Select("COUNT(*)").From("sets").Where(
+		squirrel.And{
+			squirrel.Eq{"user_id": id},
+			squirrel.Expr("exercise_name IS NOT NULL AND NOT recorded"),
+		}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Is exercise choosen ToSql error:", slog.Any("err", err))
+		return false, err
+	}
+
+	var count int
+	err = conn(ctx, s.Db).QueryRow(ctx, query, args...).Scan(&count)
+	if err != nil {
+		slog.Error("Is exercise choosen QueryRow error:", slog.Any("err", err))
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (s *SetRepositoryDB) IsSetStarted(ctx context.Context, id int64) (bool, error) {
+
+	q := squirrel.Select("COUNT(*)").From("sets").Where(
+		squirrel.And{
+			squirrel.Eq{"user_id": id},
+			squirrel.Expr("start_time IS NOT NULL AND NOT recorded"),
+		}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Is set started ToSql error:", slog.Any("err", err))
+		return false, err
+	}
+
+	var count int
+	err = conn(ctx, s.Db).QueryRow(ctx, query, args...).Scan(&count)
+	if err != nil {
+		slog.Error("Is set started QueryRow error:", slog.Any("err", err))
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// LockOpenSet returns the user's unfinished set and locks its row until the end of the transaction.
+func (s *SetRepositoryDB) LockOpenSet(ctx context.Context, id int64) (domain.Set, error) {
+
+	q := squirrel.Select("set_id", "user_id", "training_id", "exercise_name", "start_time").From("sets").Where(
+		squirrel.And{
+			squirrel.Eq{"user_id": id},
+			squirrel.Expr("NOT recorded"),
+		}).Suffix("FOR UPDATE").PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Lock open set ToSql error:", slog.Any("err", err))
+		return domain.Set{}, err
+	}
+
+	var (
+		set        domain.Set
+		trainingID *int64
+		start      *time.Time
+	)
+
+	err = conn(ctx, s.Db).QueryRow(ctx, query, args...).Scan(&set.Set_id, &set.User_id, &trainingID, &set.Exercise, &start)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Set{}, domain.ErrNoOpenSet
+	}
+	if err != nil {
+		slog.Error("Lock open set QueryRow error:", slog.Any("err", err))
+		return domain.Set{}, err
+	}
+
+	if trainingID != nil {
+		set.Training_id = *trainingID
+	}
+	if start != nil {
+		set.Start = *start
+	}
+
+	return set, nil
+}
+
+// FinishSet records the end time, weight and reps of the set in one statement.
+func (s *SetRepositoryDB) FinishSet(ctx context.Context, setID int64, endTime time.Time, weight float64, reps int) error {
+
+	q := squirrel.Update("sets").SetMap(map[string]interface{}{
+		"end_time": endTime,
+		"weight":   weight,
+		"reps":     reps,
+		"recorded": true,
+	}).Where(squirrel.Eq{"set_id": setID}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Finish set ToSql error:", slog.Any("err", err))
+		return err
+	}
+
+	_, err = conn(ctx, s.Db).Exec(ctx, query, args...)
+	if err != nil {
+		slog.Error("Finish set Exec error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+// AddSet inserts an already recorded set, zero start and end times are stored as NULL.
+func (s *SetRepositoryDB) AddSet(ctx context.Context, set domain.Set) (int64, error) {
+
+	q := squirrel.Insert("sets").
+		Columns("user_id", "training_id", "exercise_name", "start_time", "end_time", "weight", "reps", "recorded").
+		Values(set.User_id, set.Training_id, set.Exercise, nullTime(set.Start), nullTime(set.End), set.Weight, set.Reps, true).
+		Suffix("RETURNING set_id").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Add set ToSql error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	var setID int64
+	err = conn(ctx, s.Db).QueryRow(ctx, query, args...).Scan(&setID)
+	if err != nil {
+		slog.Error("Add set QueryRow error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return setID, nil
+}
+
+func (s *SetRepositoryDB) GetSet(ctx context.Context, id, setID int64) (domain.Set, error) {
+
+	q := squirrel.Select(setColumns...).From("sets").Where(
+		squirrel.And{
+			squirrel.Eq{"user_id": id, "set_id": setID},
+			squirrel.Expr("recorded"),
+		}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Get set ToSql error:", slog.Any("err", err))
+		return domain.Set{}, err
+	}
+
+	set, err := scanSet(conn(ctx, s.Db).QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Set{}, domain.ErrUnknownSet
+	}
+	if err != nil {
+		slog.Error("Get set QueryRow error:", slog.Any("err", err))
+		return domain.Set{}, err
+	}
+
+	return set, nil
+}
+
+func (s *SetRepositoryDB) GetTrainingSets(ctx context.Context, id, trainingID int64) ([]domain.Set, error) {
+
+	q := squirrel.Select(setColumns...).From("sets").Where(
+		squirrel.And{
+			squirrel.Eq{"user_id": id, "training_id": trainingID},
+			squirrel.Expr("recorded"),
+		}).OrderBy("set_id").PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Get training sets ToSql error:", slog.Any("err", err))
+		return nil, err
+	}
+
+	rows, err := conn(ctx, s.Db).Query(ctx, query, args...)
+	if err != nil {
+		slog.Error("Get training sets Query error:", slog.Any("err", err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sets []domain.Set
+	for rows.Next() {
+		set, err := scanSet(rows)
+		if err != nil {
+			slog.Error("Get training sets Scan error:", slog.Any("err", err))
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Get training sets Rows error:", slog.Any("err", err))
+		return nil, err
+	}
+
+	return sets, nil
+}
+
+func (s *SetRepositoryDB) UpdateSet(ctx context.Context, set domain.Set) error {
+
+	q := squirrel.Update("sets").SetMap(map[string]interface{}{
+		"exercise_name": set.Exercise,
+		"weight":        set.Weight,
+		"reps":          set.Reps,
+	}).Where(squirrel.Eq{"user_id": set.User_id, "set_id": set.Set_id}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Update set ToSql error:", slog.Any("err", err))
+		return err
+	}
+
+	_, err = conn(ctx, s.Db).Exec(ctx, query, args...)
+	if err != nil {
+		slog.Error("Update set Exec error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *SetRepositoryDB) DeleteSet(ctx context.Context, setID int64) error {
+
+	q := squirrel.Delete("sets").Where(squirrel.Eq{"set_id": setID}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Delete set ToSql error:", slog.Any("err", err))
+		return err
+	}
+
+	_, err = conn(ctx, s.Db).Exec(ctx, query, args...)
+	if err != nil {
+		slog.Error("Delete set Exec error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+// DeleteSets removes the user's recorded sets with ids in the range, the open set is never touched.
+func (s *SetRepositoryDB) DeleteSets(ctx context.Context, id, fromSetID, toSetID int64) (int64, error) {
+
+	q := squirrel.Delete("sets").Where(
+		squirrel.And{
+			squirrel.Eq{"user_id": id},
+			squirrel.GtOrEq{"set_id": fromSetID},
+			squirrel.LtOrEq{"set_id": toSetID},
+			squirrel.Expr("recorded"),
+		}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Delete sets ToSql error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	tag, err := conn(ctx, s.Db).Exec(ctx, query, args...)
+	if err != nil {
+		slog.Error("Delete sets Exec error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// RenameExerciseSets moves all sets of the user's exercise to another exercise name.
+func (s *SetRepositoryDB) RenameExerciseSets(ctx context.Context, id int64, exercise, newName string) error {
+
+	q := squirrel.Update("sets").Set("exercise_name", newName).Where(
+		squirrel.Eq{"user_id": id, "exercise_name": exercise}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Rename exercise sets ToSql error:", slog.Any("err", err))
+		return err
+	}
+
+	_, err = conn(ctx, s.Db).Exec(ctx, query, args...)
+	if err != nil {
+		slog.Error("Rename exercise sets Exec error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+// scanSet reads a row of setColumns. Recorded sets always have weight and reps, the rest may be NULL.
+func scanSet(row pgx.Row) (domain.Set, error) {
+
+	var (
+		set        domain.Set
+		trainingID *int64
+		start, end *time.Time
+	)
+
+	if err := row.Scan(&set.Set_id, &set.User_id, &trainingID, &set.Exercise, &set.Weight, &set.Reps, &start, &end); err != nil {
+		return domain.Set{}, err
+	}
+
+	if trainingID != nil {
+		set.Training_id = *trainingID
+	}
+	if start != nil {
+		set.Start = *start
+	}
+	if end != nil {
+		set.End = *end
+	}
+
+	return set, nil
+}
+
+func nullTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+
+	return t
+}
@@ -0,0 +1,322 @@
+package postgres
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"GymBot/internal/domain/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type StatsRepositoryDB struct {
+	Db *pgxpool.Pool
+}
+
+func NewStatsRepositoryDb(db *pgxpool.Pool) repository.StatsRepository {
+	return &StatsRepositoryDB{
+		Db: db,
+	}
+}
+
+func (st *StatsRepositoryDB) GetMostPopularExercise(ctx context.Context, id int64) (string, error) {
+
+	q := squirrel.Select("exercise_name").
+		From("sets").
+		Where(squirrel.Eq{"user_id": id}).
+		Where(squirrel.Expr("recorded")).
+		GroupBy("exercise_name").
+		OrderBy("COUNT(*) DESC").
+		Limit(1).
+		PlaceholderFormat(squirrel.Dollar)
+
+	var exercise string
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("GetMostPopularExercise ToSql Error:", slog.Any("err", err))
+		return "", err
+	}
+
+	row := conn(ctx, st.Db).QueryRow(ctx, query, args...)
+
+	err = row.Scan(&exercise)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		slog.Error("GetMostPopularExercise QueryRow Error:", slog.Any("err", err))
+		return "", err
+	}
+
+	return exercise, nil
+
+}
+
+func (st *StatsRepositoryDB) GetLeastPopularExercise(ctx context.Context, id int64) (string, error) {
+
+	q := squirrel.Select("exercise_name").
+		From("sets").
+		Where(squirrel.Eq{"user_id": id}).
+		Where(squirrel.Expr("recorded")).
+		GroupBy("exercise_name").
+		OrderBy("COUNT(*) ASC").
+		Limit(1).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("GetMostPopularExercise ToSql Error:", slog.Any("err", err))
+		return "", err
+	}
+
+	var exercise string
+
+	err = conn(ctx, st.Db).QueryRow(ctx, query, args...).Scan(&exercise)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		slog.Error("GetMostPopularExercise QueryRow Error:", slog.Any("err", err))
+		return "", err
+	}
+
+	return exercise, nil
+}
+
+func (st *StatsRepositoryDB) GetAverageWeight(ctx context.Context, id int64, exercise string) (float64, error) {
+	q := squirrel.Select("AVG(weight)").From("sets").Where(
+		squirrel.And{
+			squirrel.Eq{"user_id": id},
+			squirrel.Eq{"exercise_name": exercise},
+		}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("GetAverageWeight ToSql Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	var weight *float64
+
+	err = conn(ctx, st.Db).QueryRow(ctx, query, args...).Scan(&weight)
+	if err != nil {
+		slog.Error("GetAverageWeight QueryRow Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	if weight == nil {
+		return 0, nil
+	}
+
+	return *weight, nil
+}
+
+func (st *StatsRepositoryDB) GetAverageReps(ctx context.Context, id int64, exercise string) (string, error) {
+
+	q := squirrel.Select("AVG(reps)").From("sets").Where(squirrel.Eq{
+		"user_id":       id,
+		"exercise_name": exercise,
+	}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("GetAverageReps ToSql Error:", slog.Any("err", err))
+		return "", err
+	}
+
+	var reps *float64
+
+	err = conn(ctx, st.Db).QueryRow(ctx, query, args...).Scan(&reps)
+	if err != nil {
+		slog.Error("GetAverageReps QueryRow Error:", slog.Any("err", err))
+		return "", err
+	}
+
+	result := "0"
+
+	if reps != nil {
+		result = fmt.Sprintf("%.1f", *reps)
+	}
+
+	return result, nil
+
+}
+
+func (st *StatsRepositoryDB) GetAverageTrainingsLenght(ctx context.Context, id int64) (time.Duration, error) {
+
+	q := squirrel.Select("COALESCE(EXTRACT(EPOCH FROM AVG(end_time - start_time)), 0)").From("trainings").Where(
+		squirrel.And{
+			squirrel.Eq{"user_id": id},
+			squirrel.Expr("end_time IS NOT NULL"),
+		}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("GetAverageTrainingsLenght ToSql Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	var seconds float64
+
+	err = conn(ctx, st.Db).QueryRow(ctx, query, args...).Scan(&seconds)
+	if err != nil {
+		slog.Error("GetAverageTrainingsLenght QueryRow Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+
+}
+
+func (st *StatsRepositoryDB) GetTrainingsCount(ctx context.Context, id int64) (int64, error) {
+	q := squirrel.Select("COUNT(*)").From("trainings").Where(
+		squirrel.Eq{"user_id": id}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("GetTrainingsCount ToSql Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	var count int64
+
+	err = conn(ctx, st.Db).QueryRow(ctx, query, args...).Scan(&count)
+	if err != nil {
+		slog.Error("GetTrainingsCount QueryRow Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return count, nil
+
+}
+
+func (st *StatsRepositoryDB) GetTotalSetsPerExercise(ctx context.Context, id int64, exercise string) (int64, error) {
+
+	q := squirrel.Select("COUNT(*)").From("sets").Where(
+		squirrel.And{
+			squirrel.Eq{"user_id": id},
+			squirrel.Eq{"exercise_name": exercise},
+			squirrel.Expr("recorded"),
+		}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("GetTotalSetsPerExercise ToSql Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	var totalSets int64
+
+	err = conn(ctx, st.Db).QueryRow(ctx, query, args...).Scan(&totalSets)
+	if err != nil {
+		slog.Error("GetTotalSetsPerExercise QueryRow Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return totalSets, nil
+}
+
+func (st *StatsRepositoryDB) GetSetsCount(ctx context.Context, training domain.Training, exercise string) (int, error) {
+
+	q := squirrel.Select("COUNT(*)").From("sets").Where(squirrel.Eq{
+		"training_id":   training.Training_id,
+		"exercise_name": exercise,
+	}).Where(squirrel.Expr("recorded")).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("GetSetsCount ToSql Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	var count int
+
+	err = conn(ctx, st.Db).QueryRow(ctx, query, args...).Scan(&count)
+	if err != nil {
+		slog.Error("GetSetsCount QueryRow Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return count, nil
+
+}
+
+func (st *StatsRepositoryDB) GetAverageSetsPerExerise(ctx context.Context, id int64, exercise string) (string, error) {
+
+	perTraining := squirrel.Select("COUNT(s.set_id) AS sets").
+		From("trainings t").
+		LeftJoin("sets s ON s.training_id = t.training_id AND s.recorded AND s.exercise_name = ?", exercise).
+		Where(squirrel.Eq{"t.user_id": id}).
+		GroupBy("t.training_id")
+
+	avg, err := st.averageOver(ctx, perTraining, "sets")
+	if err != nil {
+		slog.Error("GetAverageSetsPerExerise Error:", slog.Any("err", err))
+		return "", err
+	}
+
+	return fmt.Sprintf("%.2f", avg), nil
+
+}
+
+func (st *StatsRepositoryDB) GetAverageExercisesPerTraining(ctx context.Context, id int64) (float64, error) {
+
+	perTraining := squirrel.Select("COUNT(DISTINCT s.exercise_name) AS exercises").
+		From("trainings t").
+		LeftJoin("sets s ON s.training_id = t.training_id AND s.recorded").
+		Where(squirrel.Eq{"t.user_id": id}).
+		GroupBy("t.training_id")
+
+	avg, err := st.averageOver(ctx, perTraining, "exercises")
+	if err != nil {
+		slog.Error("GetAverageExercisesPerTraining Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return avg, nil
+
+}
+
+func (st *StatsRepositoryDB) GetAverageSetsPerTraining(ctx context.Context, id int64) (float64, error) {
+
+	perTraining := squirrel.Select("COUNT(s.set_id) AS sets").
+		From("trainings t").
+		LeftJoin("sets s ON s.training_id = t.training_id AND s.recorded").
+		Where(squirrel.Eq{"t.user_id": id}).
+		GroupBy("t.training_id")
+
+	avg, err := st.averageOver(ctx, perTraining, "sets")
+	if err != nil {
+		slog.Error("GetAverageSetsPerTraining Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return avg, nil
+}
+
+// averageOver returns the average of the column over the rows of a per-training subquery, 0 when there are none.
+func (st *StatsRepositoryDB) averageOver(ctx context.Context, perTraining squirrel.SelectBuilder, column string) (float64, error) {
+
+	q := squirrel.Select(fmt.Sprintf("COALESCE(AVG(per_training.%s), 0)", column)).
+		FromSelect(perTraining, "per_training").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var avg float64
+
+	err = conn(ctx, st.Db).QueryRow(ctx, query, args...).Scan(&avg)
+	if err != nil {
+		return 0, err
+	}
+
+	return avg, nil
+}
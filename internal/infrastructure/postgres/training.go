@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"GymBot/internal/domain/repository"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TrainingRepositoryDB struct {
+	Db *pgxpool.Pool
+}
+
+func NewTrainingRepositoryDb(db *pgxpool.Pool) repository.TrainingRepository {
+	return &TrainingRepositoryDB{
+		Db: db,
+	}
+}
+
+func (t *TrainingRepositoryDB) StartTrainig(ctx context.Context, id int64, startTime time.Time) error {
+
+	q := squirrel.Insert("trainings").Columns("user_id", "start_time").Values(id, startTime).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+
+	if err != nil {
+		slog.Error("Start Training ToSql error:", slog.Any("err", err))
+		return err
+	}
+
+	_, err = conn(ctx, t.Db).Exec(ctx, query, args...)
+	if constraintViolated(err, uniqueViolation, "trainings_one_active_per_user_idx") {
+		return domain.ErrTrainingAlreadyActive
+	}
+	if err != nil {
+		slog.Error("Start Trainig Exec Error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+func (t *TrainingRepositoryDB) EndTraining(ctx context.Context, id int64, endTime time.Time) error {
+
+	q := squirrel.Update("trainings").Set("end_time", endTime).Where(
+		squirrel.And{
+			squirrel.Eq{"user_id": id},
+			squirrel.Expr("end_time IS NULL"),
+		}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("End Training ToSql error:", slog.Any("err", err))
+		return err
+	}
+
+	_, err = conn(ctx, t.Db).Exec(ctx, query, args...)
+	if err != nil {
+		slog.Error("End training error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+func (t *TrainingRepositoryDB) IsTrainingActive(ctx context.Context, id int64) (bool, error) {
+
+	q := squirrel.Select("COUNT(*)").
+		From("trainings").
+		Where(
+			squirrel.And{
+				squirrel.Eq{"user_id": id},
+				squirrel.Expr("start_time IS NOT NULL"),
+				squirrel.Expr("end_time IS NULL"),
+			}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("IsTrainingActive ToSql error:", slog.Any("err", err))
+		return false, err
+	}
+
+	var count int
+
+	err = conn(ctx, t.Db).QueryRow(ctx, query, args...).Scan(&count)
+	if err != nil {
+		slog.Error("IsTrainingActive QueryRow error:", slog.Any("err", err))
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// GetActiveTraining returns the user's training that has not ended yet.
+func (t *TrainingRepositoryDB) GetActiveTraining(ctx context.Context, id int64) (domain.Training, error) {
+
+	q := squirrel.Select("training_id", "user_id", "start_time").From("trainings").Where(
+		squirrel.And{
+			squirrel.Eq{"user_id": id},
+			squirrel.Expr("end_time IS NULL"),
+		}).OrderBy("start_time DESC").Limit(1).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("GetActiveTraining ToSql error:", slog.Any("err", err))
+		return domain.Training{}, err
+	}
+
+	var training domain.Training
+	err = conn(ctx, t.Db).QueryRow(ctx, query, args...).Scan(&training.Training_id, &training.User_id, &training.Start)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Training{}, domain.ErrNoActiveTraining
+	}
+	if err != nil {
+		slog.Error("GetActiveTraining QueryRow error:", slog.Any("err", err))
+		return domain.Training{}, err
+	}
+
+	return training, nil
+}
+
+func (t *TrainingRepositoryDB) GetTrainings(ctx context.Context, id int64) ([]domain.Training, error) {
+	var trainings []domain.Training
+
+	q := squirrel.Select("training_id", "user_id", "start_time", "end_time").From("trainings").Where(
+		squirrel.Eq{"user_id": id}).OrderBy("start_time").PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("GetTrainingsByUserID ToSql Error:", slog.Any("err", err))
+		return nil, err
+	}
+
+	rows, err := conn(ctx, t.Db).Query(ctx, query, args...)
+	if err != nil {
+		slog.Error("GetTrainingsByUserID Query Error:", slog.Any("err", err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			training domain.Training
+			end      *time.Time
+		)
+		if err := rows.Scan(
+			&training.Training_id,
+			&training.User_id,
+			&training.Start,
+			&end,
+		); err != nil {
+			slog.Error("GetTrainingsByUserID Scan Error:", slog.Any("err", err))
+			return nil, err
+		}
+		if end != nil {
+			training.End = *end
+		}
+		trainings = append(trainings, training)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("GetTrainingsByUserID Rows Error:", slog.Any("err", err))
+		return nil, err
+	}
+
+	return trainings, nil
+}
+
+func (t *TrainingRepositoryDB) GetTraining(ctx context.Context, id, trainingID int64) (domain.Training, error) {
+
+	q := squirrel.Select("training_id", "user_id", "start_time", "end_time").From("trainings").Where(
+		squirrel.Eq{"user_id": id, "training_id": trainingID}).PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("GetTraining ToSql error:", slog.Any("err", err))
+		return domain.Training{}, err
+	}
+
+	var (
+		training domain.Training
+		end      *time.Time
+	)
+
+	err = conn(ctx, t.Db).QueryRow(ctx, query, args...).Scan(&training.Training_id, &training.User_id, &training.Start, &end)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Training{}, domain.ErrUnknownTraining
+	}
+	if err != nil {
+		slog.Error("GetTraining QueryRow error:", slog.Any("err", err))
+		return domain.Training{}, err
+	}
+
+	if end != nil {
+		training.End = *end
+	}
+
+	return training, nil
+}
+
+// AddTraining inserts a training that was not tracked live, it is finished from the start.
+func (t *TrainingRepositoryDB) AddTraining(ctx context.Context, training domain.Training) (int64, error) {
+
+	q := squirrel.Insert("trainings").Columns("user_id", "start_time", "end_time").
+		Values(training.User_id, training.Start, training.End).
+		Suffix("RETURNING training_id").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Add training ToSql error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	var trainingID int64
+	err = conn(ctx, t.Db).QueryRow(ctx, query, args...).Scan(&trainingID)
+	if err != nil {
+		slog.Error("Add training QueryRow error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return trainingID, nil
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"GymBot/internal/domain/repository"
+	"context"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type txKey struct{}
+
+// executor is the part of the pool and of a transaction the repositories need.
+type executor interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// conn returns the transaction started by UnitOfWorkDB.Do if ctx carries one, otherwise the pool.
+func conn(ctx context.Context, db *pgxpool.Pool) executor {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+
+	return db
+}
+
+type UnitOfWorkDB struct {
+	Db *pgxpool.Pool
+}
+
+func NewUnitOfWorkDb(db *pgxpool.Pool) repository.UnitOfWork {
+	return &UnitOfWorkDB{
+		Db: db,
+	}
+}
+
+// Do runs fn in a transaction. Repository calls made with the ctx passed to fn join it.
+// A nested Do joins the outer transaction.
+func (u *UnitOfWorkDB) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := u.Db.Begin(ctx)
+	if err != nil {
+		slog.Error("Begin tx error:", slog.Any("err", err))
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			slog.Error("Rollback tx error:", slog.Any("err", rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("Commit tx error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"GymBot/internal/domain/repository"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type DialogRepositoryDB struct {
+	Db *sql.DB
+}
+
+func NewDialogRepositoryDb(db *sql.DB) repository.DialogRepository {
+	return &DialogRepositoryDB{
+		Db: db,
+	}
+}
+
+func (d *DialogRepositoryDB) GetDialog(ctx context.Context, id int64) (*domain.Dialog, error) {
+
+	q := squirrel.Select("user_id", "state", "data", "expires_at").From("dialogs").Where(
+		squirrel.Eq{"user_id": id}).PlaceholderFormat(squirrel.Question)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("GetDialog ToSql Error:", slog.Any("err", err))
+		return nil, err
+	}
+
+	var (
+		dialog domain.Dialog
+		data   string
+	)
+
+	err = conn(ctx, d.Db).QueryRowContext(ctx, query, args...).Scan(&dialog.User_id, &dialog.State, &data, &dialog.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		slog.Error("GetDialog QueryRow Error:", slog.Any("err", err))
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(data), &dialog.Data); err != nil {
+		slog.Error("GetDialog Unmarshal Error:", slog.Any("err", err))
+		return nil, err
+	}
+
+	return &dialog, nil
+}
+
+func (d *DialogRepositoryDB) SaveDialog(ctx context.Context, dialog domain.Dialog) error {
+
+	data, err := json.Marshal(dialog.Data)
+	if err != nil {
+		slog.Error("SaveDialog Marshal Error:", slog.Any("err", err))
+		return err
+	}
+
+	q := squirrel.Insert("dialogs").Columns("user_id", "state", "data", "expires_at").
+		Values(dialog.User_id, dialog.State, string(data), dialog.ExpiresAt.UTC()).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET state = excluded.state, data = excluded.data, expires_at = excluded.expires_at").
+		PlaceholderFormat(squirrel.Question)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("SaveDialog ToSql Error:", slog.Any("err", err))
+		return err
+	}
+
+	_, err = conn(ctx, d.Db).ExecContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("SaveDialog Exec Error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+func (d *DialogRepositoryDB) DeleteDialog(ctx context.Context, id int64) error {
+
+	q := squirrel.Delete("dialogs").Where(squirrel.Eq{"user_id": id}).PlaceholderFormat(squirrel.Question)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("DeleteDialog ToSql Error:", slog.Any("err", err))
+		return err
+	}
+
+	_, err = conn(ctx, d.Db).ExecContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("DeleteDialog Exec Error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package sqlite
+
+import (
+	"errors"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	uniqueViolation = sqlite3.SQLITE_CONSTRAINT_UNIQUE
+	checkViolation  = sqlite3.SQLITE_CONSTRAINT_CHECK
+)
+
+// constraintViolated reports whether err was caused by a constraint failing with the given code.
+// SQLite reports a unique index by its columns, so target is "table.column" for unique violations
+// and the constraint name for check violations.
+func constraintViolated(err error, code int, target string) bool {
+
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	return sqliteErr.Code() == code && strings.Contains(sqliteErr.Error(), target)
+}
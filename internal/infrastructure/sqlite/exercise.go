@@ -0,0 +1,229 @@
+package sqlite
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"GymBot/internal/domain/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/Masterminds/squirrel"
+)
+
+var exerciseColumns = []string{"exercise_id", "user_id", "name", "archived", "favorite"}
+
+// lastTraining selects the id of the user's latest training.
+const lastTraining = "(SELECT training_id FROM trainings WHERE user_id = ? ORDER BY start_time DESC LIMIT 1)"
+
+// exerciseOrderBy is the ORDER BY of every picker order, ties are broken by exercise_id.
+var exerciseOrderBy = map[domain.ExerciseOrder]string{
+	domain.OrderAdded: "exercise_id",
+	domain.OrderName:  "unicode_lower(name), exercise_id",
+	domain.OrderMostUsed: "(SELECT COUNT(*) FROM sets s WHERE s.user_id = exercises.user_id AND s.exercise_name = exercises.name " +
+		"AND s.recorded) DESC, exercise_id",
+	domain.OrderRecent: "(SELECT MAX(t.start_time) FROM sets s JOIN trainings t ON t.training_id = s.training_id " +
+		"WHERE s.user_id = exercises.user_id AND s.exercise_name = exercises.name) DESC, exercise_id",
+}
+
+type ExerciseRepositoryDB struct {
+	Db *sql.DB
+}
+
+func NewExerciseRepositoryDb(db *sql.DB) repository.ExerciseRepository {
+	return &ExerciseRepositoryDB{
+		Db: db,
+	}
+}
+
+func (e *ExerciseRepositoryDB) AddExercise(ctx context.Context, id int64, exercise string) error {
+
+	q := squirrel.Insert("exercises").Columns("name", "name_key", "user_id").
+		Values(exercise, domain.ExerciseNameKey(exercise), id).PlaceholderFormat(squirrel.Question)
+
+	return e.exec(ctx, q)
+}
+
+// GetExercise returns the exercise only if it belongs to the user, otherwise ErrUnknownExercise.
+func (e *ExerciseRepositoryDB) GetExercise(ctx context.Context, id, exerciseID int64) (domain.Exercise, error) {
+
+	q := squirrel.Select(exerciseColumns...).From("exercises").Where(
+		squirrel.Eq{"user_id": id, "exercise_id": exerciseID}).PlaceholderFormat(squirrel.Question)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("GetExercise ToSql Error:", slog.Any("err", err))
+		return domain.Exercise{}, err
+	}
+
+	var exercise domain.Exercise
+
+	err = conn(ctx, e.Db).QueryRowContext(ctx, query, args...).Scan(&exercise.Exercise_id, &exercise.User_id, &exercise.Name, &exercise.Archived, &exercise.Favorite)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Exercise{}, domain.ErrUnknownExercise
+	}
+	if err != nil {
+		slog.Error("GetExercise QueryRow Error:", slog.Any("err", err))
+		return domain.Exercise{}, err
+	}
+
+	return exercise, nil
+}
+
+func (e *ExerciseRepositoryDB) GetExercises(ctx context.Context, id int64) ([]domain.Exercise, error) {
+
+	q := squirrel.Select(exerciseColumns...).From("exercises").Where(
+		squirrel.Eq{"user_id": id, "archived": false}).OrderBy("exercise_id").PlaceholderFormat(squirrel.Question)
+
+	return e.exercises(ctx, q)
+}
+
+func (e *ExerciseRepositoryDB) CountExercises(ctx context.Context, id int64) (int64, error) {
+
+	q := squirrel.Select("COUNT(*)").From("exercises").Where(
+		squirrel.Eq{"user_id": id, "archived": false}).PlaceholderFormat(squirrel.Question)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("CountExercises ToSql Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	var count int64
+	err = conn(ctx, e.Db).QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		slog.Error("CountExercises QueryRow Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (e *ExerciseRepositoryDB) GetPage(ctx context.Context, id int64, order domain.ExerciseOrder, offset, limit int64) ([]domain.Exercise, error) {
+
+	q := squirrel.Select(exerciseColumns...).
+		From("exercises").
+		Where(squirrel.Eq{"user_id": id, "archived": false}).
+		OrderBy(exerciseOrderBy[order]).
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		PlaceholderFormat(squirrel.Question)
+
+	return e.exercises(ctx, q)
+}
+
+func (e *ExerciseRepositoryDB) GetArchivedExercises(ctx context.Context, id int64) ([]domain.Exercise, error) {
+
+	q := squirrel.Select(exerciseColumns...).From("exercises").Where(
+		squirrel.Eq{"user_id": id, "archived": true}).OrderBy("name").PlaceholderFormat(squirrel.Question)
+
+	return e.exercises(ctx, q)
+}
+
+func (e *ExerciseRepositoryDB) GetFavoriteExercises(ctx context.Context, id int64) ([]domain.Exercise, error) {
+
+	q := squirrel.Select(exerciseColumns...).From("exercises").Where(
+		squirrel.Eq{"user_id": id, "archived": false, "favorite": true}).OrderBy("unicode_lower(name)").PlaceholderFormat(squirrel.Question)
+
+	return e.exercises(ctx, q)
+}
+
+func (e *ExerciseRepositoryDB) GetLastTrainingExercises(ctx context.Context, id int64) ([]domain.Exercise, error) {
+
+	q := squirrel.Select(exerciseColumns...).
+		From("exercises").
+		Where(squirrel.Eq{"user_id": id, "archived": false}).
+		Where("name IN (SELECT exercise_name FROM sets WHERE training_id = "+lastTraining+")", id).
+		OrderByClause("(SELECT MIN(set_id) FROM sets WHERE training_id = "+lastTraining+" AND exercise_name = exercises.name)", id).
+		PlaceholderFormat(squirrel.Question)
+
+	return e.exercises(ctx, q)
+}
+
+func (e *ExerciseRepositoryDB) RenameExercise(ctx context.Context, id, exerciseID int64, newName string) error {
+
+	q := squirrel.Update("exercises").SetMap(map[string]interface{}{
+		"name":     newName,
+		"name_key": domain.ExerciseNameKey(newName),
+	}).Where(squirrel.Eq{"user_id": id, "exercise_id": exerciseID}).PlaceholderFormat(squirrel.Question)
+
+	return e.exec(ctx, q)
+}
+
+func (e *ExerciseRepositoryDB) SetExerciseArchived(ctx context.Context, id, exerciseID int64, archived bool) error {
+
+	q := squirrel.Update("exercises").Set("archived", archived).Where(
+		squirrel.Eq{"user_id": id, "exercise_id": exerciseID}).PlaceholderFormat(squirrel.Question)
+
+	return e.exec(ctx, q)
+}
+
+func (e *ExerciseRepositoryDB) SetExerciseFavorite(ctx context.Context, id, exerciseID int64, favorite bool) error {
+
+	q := squirrel.Update("exercises").Set("favorite", favorite).Where(
+		squirrel.Eq{"user_id": id, "exercise_id": exerciseID}).PlaceholderFormat(squirrel.Question)
+
+	return e.exec(ctx, q)
+}
+
+func (e *ExerciseRepositoryDB) DeleteExercise(ctx context.Context, id, exerciseID int64) error {
+
+	q := squirrel.Delete("exercises").Where(
+		squirrel.Eq{"user_id": id, "exercise_id": exerciseID}).PlaceholderFormat(squirrel.Question)
+
+	return e.exec(ctx, q)
+}
+
+func (e *ExerciseRepositoryDB) exec(ctx context.Context, q squirrel.Sqlizer) error {
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Exercise ToSql Error:", slog.Any("err", err))
+		return err
+	}
+
+	_, err = conn(ctx, e.Db).ExecContext(ctx, query, args...)
+	if constraintViolated(err, uniqueViolation, "exercises.name_key") {
+		return domain.ErrExerciseExists
+	}
+	if err != nil {
+		slog.Error("Exercise Exec Error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+// exercises runs a query selecting exerciseColumns.
+func (e *ExerciseRepositoryDB) exercises(ctx context.Context, q squirrel.SelectBuilder) ([]domain.Exercise, error) {
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Exercises ToSql Error:", slog.Any("err", err))
+		return nil, err
+	}
+
+	rows, err := conn(ctx, e.Db).QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("Exercises Query Error:", slog.Any("err", err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exercises []domain.Exercise
+	for rows.Next() {
+		var exercise domain.Exercise
+		if err := rows.Scan(&exercise.Exercise_id, &exercise.User_id, &exercise.Name, &exercise.Archived, &exercise.Favorite); err != nil {
+			slog.Error("Exercises Scan Error:", slog.Any("err", err))
+			return nil, err
+		}
+		exercises = append(exercises, exercise)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Exercises Rows Error:", slog.Any("err", err))
+		return nil, err
+	}
+
+	return exercises, nil
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS dialogs;
+DROP TABLE IF EXISTS sets;
+DROP TABLE IF EXISTS trainings;
+DROP TABLE IF EXISTS exercises;
+DROP TABLE IF EXISTS users;
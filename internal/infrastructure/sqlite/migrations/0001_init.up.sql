@@ -0,0 +1,50 @@
+CREATE TABLE IF NOT EXISTS users (
+    user_id INTEGER PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS exercises (
+    exercise_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     INTEGER NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    name        TEXT    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS exercises_user_id_idx ON exercises (user_id);
+
+CREATE TABLE IF NOT EXISTS trainings (
+    training_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     INTEGER   NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    start_time  TIMESTAMP NOT NULL,
+    end_time    TIMESTAMP,
+    CONSTRAINT trainings_time_order CHECK (end_time IS NULL OR julianday(end_time) >= julianday(start_time))
+);
+
+CREATE INDEX IF NOT EXISTS trainings_user_id_start_time_idx ON trainings (user_id, start_time);
+
+CREATE UNIQUE INDEX IF NOT EXISTS trainings_one_active_per_user_idx ON trainings (user_id) WHERE end_time IS NULL;
+
+CREATE TABLE IF NOT EXISTS sets (
+    set_id        INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id       INTEGER NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    training_id   INTEGER REFERENCES trainings (training_id) ON DELETE SET NULL,
+    exercise_name TEXT    NOT NULL,
+    start_time    TIMESTAMP,
+    end_time      TIMESTAMP,
+    weight        REAL,
+    reps          INTEGER,
+    CONSTRAINT sets_weight_non_negative CHECK (weight IS NULL OR weight >= 0),
+    CONSTRAINT sets_reps_non_negative CHECK (reps IS NULL OR reps >= 0),
+    CONSTRAINT sets_training_required CHECK (training_id IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS sets_user_id_exercise_name_idx ON sets (user_id, exercise_name);
+
+CREATE INDEX IF NOT EXISTS sets_training_id_idx ON sets (training_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS sets_one_open_per_user_idx ON sets (user_id) WHERE end_time IS NULL;
+
+CREATE TABLE IF NOT EXISTS dialogs (
+    user_id    INTEGER PRIMARY KEY REFERENCES users (user_id) ON DELETE CASCADE,
+    state      TEXT      NOT NULL,
+    data       TEXT      NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMP NOT NULL
+);
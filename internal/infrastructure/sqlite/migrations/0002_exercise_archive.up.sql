@@ -0,0 +1,2 @@
+-- Archived exercises are hidden from the picker but keep their sets and statistics.
+ALTER TABLE exercises ADD COLUMN archived INTEGER NOT NULL DEFAULT 0;
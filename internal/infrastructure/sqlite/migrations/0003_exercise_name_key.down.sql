@@ -0,0 +1,4 @@
+-- Merged duplicates are not restored.
+DROP INDEX IF EXISTS exercises_user_id_name_key_idx;
+
+ALTER TABLE exercises DROP COLUMN name_key;
@@ -0,0 +1,38 @@
+-- name_key is the form exercise names are compared in. normalize_space and exercise_name_key
+-- are Go functions registered by the sqlite package, so existing rows get exactly the keys new ones do.
+ALTER TABLE exercises ADD COLUMN name_key TEXT;
+
+UPDATE exercises SET name = normalize_space(name);
+
+UPDATE sets SET exercise_name = normalize_space(exercise_name);
+
+UPDATE exercises SET name_key = exercise_name_key(name);
+
+-- Merge exercises with the same key into the oldest one: move their sets, then drop them.
+UPDATE sets
+SET exercise_name = (
+    SELECT keep.name
+    FROM exercises dup
+    JOIN exercises keep ON keep.user_id = dup.user_id AND keep.name_key = dup.name_key
+    WHERE dup.user_id = sets.user_id
+      AND dup.name = sets.exercise_name
+    ORDER BY keep.exercise_id
+    LIMIT 1
+)
+WHERE EXISTS (
+    SELECT 1
+    FROM exercises dup
+    WHERE dup.user_id = sets.user_id
+      AND dup.name = sets.exercise_name
+);
+
+DELETE FROM exercises
+WHERE EXISTS (
+    SELECT 1
+    FROM exercises keep
+    WHERE keep.user_id = exercises.user_id
+      AND keep.name_key = exercises.name_key
+      AND keep.exercise_id < exercises.exercise_id
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS exercises_user_id_name_key_idx ON exercises (user_id, name_key);
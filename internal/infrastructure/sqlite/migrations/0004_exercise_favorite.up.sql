@@ -0,0 +1,2 @@
+-- Favourite exercises are pinned at the top of the picker.
+ALTER TABLE exercises ADD COLUMN favorite INTEGER NOT NULL DEFAULT 0;
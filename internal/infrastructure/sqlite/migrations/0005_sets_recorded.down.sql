@@ -0,0 +1,13 @@
+-- Sets logged without the timers get the start of their training as the end time.
+UPDATE sets
+SET end_time = (SELECT t.start_time FROM trainings t WHERE t.training_id = sets.training_id)
+WHERE recorded = 1
+  AND end_time IS NULL;
+
+DELETE FROM sets WHERE recorded = 1 AND end_time IS NULL;
+
+DROP INDEX IF EXISTS sets_one_open_per_user_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS sets_one_open_per_user_idx ON sets (user_id) WHERE end_time IS NULL;
+
+ALTER TABLE sets DROP COLUMN recorded;
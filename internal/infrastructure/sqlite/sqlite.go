@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net/url"
+	"strings"
+
+	"modernc.org/sqlite"
+)
+
+// SQLite has no regexp and lowers ASCII letters only, so the migrations normalize
+// exercise names with the same Go code the application uses, and the picker sorts
+// them alphabetically with unicode_lower.
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("normalize_space", 1, textFunc(func(s string) string {
+		return strings.Join(strings.Fields(s), " ")
+	}))
+	sqlite.MustRegisterDeterministicScalarFunction("exercise_name_key", 1, textFunc(domain.ExerciseNameKey))
+	sqlite.MustRegisterDeterministicScalarFunction("unicode_lower", 1, textFunc(strings.ToLower))
+}
+
+func textFunc(fn func(string) string) func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+	return func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return args[0], nil
+		}
+		return fn(s), nil
+	}
+}
+
+// Open opens the database file at path, creating it if needed, and applies pending migrations.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+
+	db, err := Connect(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Connect opens the database file at path, creating it if needed, without touching the schema.
+//
+// The pool is limited to a single connection: SQLite allows one writer at a time anyway, and it makes
+// a transaction started by UnitOfWorkDB.Do exclusive, which replaces SELECT ... FOR UPDATE.
+func Connect(ctx context.Context, path string) (*sql.DB, error) {
+
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_time_format", "sqlite")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
@@ -0,0 +1,134 @@
+package sqlite_test
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"GymBot/internal/domain/repository/repositorytest"
+	"GymBot/internal/infrastructure/sqlite"
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Storage {
+
+		db := open(t)
+
+		return repositorytest.Storage{
+			Users:     sqlite.NewUserRepositoryDb(db),
+			Trainings: sqlite.NewTrainingRepositoryDb(db),
+			Sets:      sqlite.NewSetRepositoryDb(db),
+			Exercises: sqlite.NewExerciseRepositoryDb(db),
+			Stats:     sqlite.NewStatsRepositoryDb(db),
+			Dialogs:   sqlite.NewDialogRepositoryDb(db),
+			Tx:        sqlite.NewUnitOfWorkDb(db),
+		}
+	})
+}
+
+// TestMigrateDownUp rolls every migration back and applies them again.
+func TestMigrateDownUp(t *testing.T) {
+
+	ctx := context.Background()
+	db := open(t)
+
+	migrator, err := sqlite.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range migrator.Migrations {
+		if _, err := migrator.Down(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := migrator.Down(ctx); !errors.Is(err, sqlite.ErrNoMigrationToRollback) {
+		t.Fatalf("Down with nothing applied: %v, want ErrNoMigrationToRollback", err)
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(migrator.Migrations) {
+		t.Fatalf("Up applied %d migrations, want %d", len(applied), len(migrator.Migrations))
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range statuses {
+		if !s.Applied {
+			t.Errorf("migration %04d_%s is not applied", s.Version, s.Name)
+		}
+	}
+}
+
+// TestMigrateSetsRecorded checks sets stored before the recorded column: finished sets become recorded,
+// the ones ended without weight or reps are dropped and the unfinished one stays open.
+func TestMigrateSetsRecorded(t *testing.T) {
+
+	ctx := context.Background()
+	db := open(t)
+
+	migrator, err := sqlite.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Down(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO users (user_id) VALUES (1);
+		INSERT INTO trainings (training_id, user_id, start_time) VALUES (1, 1, '2026-01-10 18:00:00');
+		INSERT INTO sets (set_id, user_id, training_id, exercise_name, start_time, end_time, weight, reps) VALUES
+			(1, 1, 1, 'Присед', '2026-01-10 18:01:00', '2026-01-10 18:02:00', 100, 5),
+			(2, 1, 1, 'Присед', '2026-01-10 18:03:00', '2026-01-10 18:04:00', NULL, NULL),
+			(3, 1, 1, 'Жим', '2026-01-10 18:05:00', NULL, NULL, NULL);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	sets := sqlite.NewSetRepositoryDb(db)
+
+	recorded, err := sets.GetTrainingSets(ctx, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recorded) != 1 || recorded[0].Set_id != 1 {
+		t.Fatalf("GetTrainingSets = %+v, want set 1 only", recorded)
+	}
+
+	open, err := sets.LockOpenSet(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if open.Set_id != 3 {
+		t.Fatalf("LockOpenSet = %+v, want set 3", open)
+	}
+
+	if _, err := sets.GetSet(ctx, 1, 2); !errors.Is(err, domain.ErrUnknownSet) {
+		t.Fatalf("GetSet of the half-finished set: %v, want ErrUnknownSet", err)
+	}
+}
+
+// open returns a migrated database in a temporary file.
+func open(t *testing.T) *sql.DB {
+
+	db, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "gymbot.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
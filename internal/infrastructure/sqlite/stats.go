@@ -0,0 +1,263 @@
+package sqlite
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"GymBot/internal/domain/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type StatsRepositoryDB struct {
+	Db *sql.DB
+}
+
+func NewStatsRepositoryDb(db *sql.DB) repository.StatsRepository {
+	return &StatsRepositoryDB{
+		Db: db,
+	}
+}
+
+func (st *StatsRepositoryDB) GetMostPopularExercise(ctx context.Context, id int64) (string, error) {
+
+	exercise, err := st.exerciseBySetCount(ctx, id, "COUNT(*) DESC")
+	if err != nil {
+		slog.Error("GetMostPopularExercise Error:", slog.Any("err", err))
+		return "", err
+	}
+
+	return exercise, nil
+}
+
+func (st *StatsRepositoryDB) GetLeastPopularExercise(ctx context.Context, id int64) (string, error) {
+
+	exercise, err := st.exerciseBySetCount(ctx, id, "COUNT(*) ASC")
+	if err != nil {
+		slog.Error("GetLeastPopularExercise Error:", slog.Any("err", err))
+		return "", err
+	}
+
+	return exercise, nil
+}
+
+func (st *StatsRepositoryDB) GetAverageWeight(ctx context.Context, id int64, exercise string) (float64, error) {
+
+	q := squirrel.Select("AVG(weight)").From("sets").Where(squirrel.Eq{
+		"user_id":       id,
+		"exercise_name": exercise,
+	}).PlaceholderFormat(squirrel.Question)
+
+	weight, err := st.nullableFloat(ctx, q)
+	if err != nil {
+		slog.Error("GetAverageWeight Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return weight.Float64, nil
+}
+
+func (st *StatsRepositoryDB) GetAverageReps(ctx context.Context, id int64, exercise string) (string, error) {
+
+	q := squirrel.Select("AVG(reps)").From("sets").Where(squirrel.Eq{
+		"user_id":       id,
+		"exercise_name": exercise,
+	}).PlaceholderFormat(squirrel.Question)
+
+	reps, err := st.nullableFloat(ctx, q)
+	if err != nil {
+		slog.Error("GetAverageReps Error:", slog.Any("err", err))
+		return "", err
+	}
+
+	if !reps.Valid {
+		return "0", nil
+	}
+
+	return fmt.Sprintf("%.1f", reps.Float64), nil
+}
+
+func (st *StatsRepositoryDB) GetAverageTrainingsLenght(ctx context.Context, id int64) (time.Duration, error) {
+
+	// julianday differences are off by microseconds, so the seconds are rounded to milliseconds
+	q := squirrel.Select("AVG(ROUND((julianday(end_time) - julianday(start_time)) * 86400, 3))").From("trainings").Where(
+		squirrel.And{
+			squirrel.Eq{"user_id": id},
+			squirrel.Expr("end_time IS NOT NULL"),
+		}).PlaceholderFormat(squirrel.Question)
+
+	seconds, err := st.nullableFloat(ctx, q)
+	if err != nil {
+		slog.Error("GetAverageTrainingsLenght Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return time.Duration(seconds.Float64 * float64(time.Second)), nil
+}
+
+func (st *StatsRepositoryDB) GetTrainingsCount(ctx context.Context, id int64) (int64, error) {
+
+	q := squirrel.Select("COUNT(*)").From("trainings").Where(
+		squirrel.Eq{"user_id": id}).PlaceholderFormat(squirrel.Question)
+
+	count, err := st.count(ctx, q)
+	if err != nil {
+		slog.Error("GetTrainingsCount Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (st *StatsRepositoryDB) GetTotalSetsPerExercise(ctx context.Context, id int64, exercise string) (int64, error) {
+
+	q := squirrel.Select("COUNT(*)").From("sets").Where(squirrel.Eq{
+		"user_id":       id,
+		"exercise_name": exercise,
+	}).Where(squirrel.Expr("recorded = 1")).PlaceholderFormat(squirrel.Question)
+
+	count, err := st.count(ctx, q)
+	if err != nil {
+		slog.Error("GetTotalSetsPerExercise Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (st *StatsRepositoryDB) GetSetsCount(ctx context.Context, training domain.Training, exercise string) (int, error) {
+
+	q := squirrel.Select("COUNT(*)").From("sets").Where(squirrel.Eq{
+		"training_id":   training.Training_id,
+		"exercise_name": exercise,
+	}).Where(squirrel.Expr("recorded = 1")).PlaceholderFormat(squirrel.Question)
+
+	count, err := st.count(ctx, q)
+	if err != nil {
+		slog.Error("GetSetsCount Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (st *StatsRepositoryDB) GetAverageSetsPerExerise(ctx context.Context, id int64, exercise string) (string, error) {
+
+	perTraining := squirrel.Select("COUNT(s.set_id) AS sets").
+		From("trainings t").
+		LeftJoin("sets s ON s.training_id = t.training_id AND s.recorded = 1 AND s.exercise_name = ?", exercise).
+		Where(squirrel.Eq{"t.user_id": id}).
+		GroupBy("t.training_id")
+
+	avg, err := st.averageOver(ctx, perTraining, "sets")
+	if err != nil {
+		slog.Error("GetAverageSetsPerExerise Error:", slog.Any("err", err))
+		return "", err
+	}
+
+	return fmt.Sprintf("%.2f", avg), nil
+}
+
+func (st *StatsRepositoryDB) GetAverageExercisesPerTraining(ctx context.Context, id int64) (float64, error) {
+
+	perTraining := squirrel.Select("COUNT(DISTINCT s.exercise_name) AS exercises").
+		From("trainings t").
+		LeftJoin("sets s ON s.training_id = t.training_id AND s.recorded = 1").
+		Where(squirrel.Eq{"t.user_id": id}).
+		GroupBy("t.training_id")
+
+	avg, err := st.averageOver(ctx, perTraining, "exercises")
+	if err != nil {
+		slog.Error("GetAverageExercisesPerTraining Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return avg, nil
+}
+
+func (st *StatsRepositoryDB) GetAverageSetsPerTraining(ctx context.Context, id int64) (float64, error) {
+
+	perTraining := squirrel.Select("COUNT(s.set_id) AS sets").
+		From("trainings t").
+		LeftJoin("sets s ON s.training_id = t.training_id AND s.recorded = 1").
+		Where(squirrel.Eq{"t.user_id": id}).
+		GroupBy("t.training_id")
+
+	avg, err := st.averageOver(ctx, perTraining, "sets")
+	if err != nil {
+		slog.Error("GetAverageSetsPerTraining Error:", slog.Any("err", err))
+		return 0, err
+	}
+
+	return avg, nil
+}
+
+// averageOver returns the average of the column over the rows of a per-training subquery, 0 when there are none.
+func (st *StatsRepositoryDB) averageOver(ctx context.Context, perTraining squirrel.SelectBuilder, column string) (float64, error) {
+
+	q := squirrel.Select(fmt.Sprintf("AVG(per_training.%s)", column)).
+		FromSelect(perTraining, "per_training").
+		PlaceholderFormat(squirrel.Question)
+
+	avg, err := st.nullableFloat(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+
+	return avg.Float64, nil
+}
+
+// exerciseBySetCount returns the first exercise of the user ordered by the number of its recorded sets,
+// an empty string when the user has no recorded sets.
+func (st *StatsRepositoryDB) exerciseBySetCount(ctx context.Context, id int64, order string) (string, error) {
+
+	q := squirrel.Select("exercise_name").From("sets").Where(squirrel.Eq{"user_id": id}).Where(squirrel.Expr("recorded = 1")).
+		GroupBy("exercise_name").OrderBy(order).Limit(1).PlaceholderFormat(squirrel.Question)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	var exercise string
+	err = conn(ctx, st.Db).QueryRowContext(ctx, query, args...).Scan(&exercise)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return exercise, nil
+}
+
+func (st *StatsRepositoryDB) count(ctx context.Context, q squirrel.SelectBuilder) (int64, error) {
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = conn(ctx, st.Db).QueryRowContext(ctx, query, args...).Scan(&count)
+
+	return count, err
+}
+
+// nullableFloat runs a single aggregate query, the result is not valid when there was nothing to aggregate.
+func (st *StatsRepositoryDB) nullableFloat(ctx context.Context, q squirrel.SelectBuilder) (sql.NullFloat64, error) {
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return sql.NullFloat64{}, err
+	}
+
+	var value sql.NullFloat64
+	err = conn(ctx, st.Db).QueryRowContext(ctx, query, args...).Scan(&value)
+
+	return value, err
+}
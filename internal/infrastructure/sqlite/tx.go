@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"GymBot/internal/domain/repository"
+	"context"
+	"database/sql"
+	"log/slog"
+)
+
+type txKey struct{}
+
+// executor is the part of the database and of a transaction the repositories need.
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// conn returns the transaction started by UnitOfWorkDB.Do if ctx carries one, otherwise the database.
+func conn(ctx context.Context, db *sql.DB) executor {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+
+	return db
+}
+
+type UnitOfWorkDB struct {
+	Db *sql.DB
+}
+
+func NewUnitOfWorkDb(db *sql.DB) repository.UnitOfWork {
+	return &UnitOfWorkDB{
+		Db: db,
+	}
+}
+
+// Do runs fn in a transaction. Repository calls made with the ctx passed to fn join it.
+// A nested Do joins the outer transaction.
+func (u *UnitOfWorkDB) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := u.Db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("Begin tx error:", slog.Any("err", err))
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			slog.Error("Rollback tx error:", slog.Any("err", rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("Commit tx error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"GymBot/internal/domain/repository"
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type UserRepositoryDB struct {
+	Db *sql.DB
+}
+
+func NewUserRepositoryDb(db *sql.DB) repository.UserRepository {
+	return &UserRepositoryDB{
+		Db: db,
+	}
+}
+
+func (u *UserRepositoryDB) RegisterUser(ctx context.Context, id int64) error {
+
+	q := squirrel.Insert("users").Columns("user_id").Values(id).PlaceholderFormat(squirrel.Question)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("Register user ToSql Error:", slog.Any("err", err))
+		return err
+	}
+
+	_, err = conn(ctx, u.Db).ExecContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("Register user Exec Error:", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+func (u *UserRepositoryDB) UserCheck(ctx context.Context, id int64) (bool, error) {
+
+	q := squirrel.Select("COUNT(*)").From("users").Where(squirrel.Eq{"user_id": id}).PlaceholderFormat(squirrel.Question)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		slog.Error("User check ToSql error:", slog.Any("err", err))
+		return false, err
+	}
+
+	var count int
+	err = conn(ctx, u.Db).QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		slog.Error("User check QueryRow error:", slog.Any("err", err))
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,182 @@
+package telegram
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"errors"
+	"fmt"
+	"gopkg.in/telebot.v3"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Callback data of an inline button is "<action>:<version>:<payload>". The version is bumped when the
+// payload of an action changes its meaning, so buttons left in old messages are answered as stale
+// instead of being decoded into something else.
+const callbackSep = ":"
+
+var errStaleCallback = errors.New("stale callback data")
+
+// Codec converts the payload of an action to and from its part of the callback data.
+type Codec[P any] struct {
+	Encode func(P) string
+	Decode func(string) (P, error)
+}
+
+var (
+	noPayload = Codec[struct{}]{
+		Encode: func(struct{}) string { return "" },
+		Decode: func(s string) (struct{}, error) {
+			if s != "" {
+				return struct{}{}, fmt.Errorf("unexpected payload %q", s)
+			}
+			return struct{}{}, nil
+		},
+	}
+
+	// idPayload carries an exercise or set id.
+	idPayload = Codec[int64]{
+		Encode: func(id int64) string { return strconv.FormatInt(id, 10) },
+		Decode: func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) },
+	}
+
+	// monthPayload carries a month of the calendar and datePayload a day, both in the bot's time zone.
+	monthPayload = timePayload("2006-01")
+	datePayload  = timePayload(dateLayout)
+)
+
+func timePayload(layout string) Codec[time.Time] {
+	return Codec[time.Time]{
+		Encode: func(t time.Time) string { return t.Format(layout) },
+		Decode: func(s string) (time.Time, error) { return time.ParseInLocation(layout, s, time.Local) },
+	}
+}
+
+// pickerPage is the payload of the exercise picker navigation: the order and the page to open.
+type pickerPage struct {
+	Order domain.ExerciseOrder
+	Page  int64
+}
+
+var pickerPayload = Codec[pickerPage]{
+	Encode: func(p pickerPage) string { return string(p.Order) + "." + strconv.FormatInt(p.Page, 10) },
+	Decode: func(s string) (pickerPage, error) {
+		order, page, _ := strings.Cut(s, ".")
+		if !domain.ExerciseOrder(order).Valid() {
+			return pickerPage{}, fmt.Errorf("unknown order %q", order)
+		}
+		n, err := strconv.ParseInt(page, 10, 64)
+		return pickerPage{Order: domain.ExerciseOrder(order), Page: n}, err
+	},
+}
+
+// setRange is the payload of the undo button of logged sets: the ids of the first and the last set.
+type setRange struct {
+	From int64
+	To   int64
+}
+
+var setRangePayload = Codec[setRange]{
+	Encode: func(r setRange) string { return strconv.FormatInt(r.From, 10) + "." + strconv.FormatInt(r.To, 10) },
+	Decode: func(s string) (setRange, error) {
+		from, to, ok := strings.Cut(s, ".")
+		if !ok {
+			return setRange{}, fmt.Errorf("malformed set range %q", s)
+		}
+		f, err := strconv.ParseInt(from, 10, 64)
+		if err != nil {
+			return setRange{}, err
+		}
+		t, err := strconv.ParseInt(to, 10, 64)
+		return setRange{From: f, To: t}, err
+	},
+}
+
+// Action is a kind of inline button together with the type of the payload it carries.
+type Action[P any] struct {
+	Name    string
+	Version int
+	Codec   Codec[P]
+}
+
+func (a Action[P]) Data(payload P) string {
+	return strings.Join([]string{a.Name, strconv.Itoa(a.Version), a.Codec.Encode(payload)}, callbackSep)
+}
+
+func (a Action[P]) Button(text string, payload P) telebot.InlineButton {
+	return telebot.InlineButton{Text: text, Data: a.Data(payload)}
+}
+
+// route binds an action to its handler, see handle and handleWith.
+type route struct {
+	action  string
+	version int
+	handle  func(b *BotHandler, c telebot.Context, payload string) error
+}
+
+// handle routes an action without payload.
+func handle(a Action[struct{}], h func(b *BotHandler, c telebot.Context) error) route {
+	return handleWith(a, func(b *BotHandler, c telebot.Context, _ struct{}) error {
+		return h(b, c)
+	})
+}
+
+// handleWith routes an action to a handler that receives the decoded payload.
+func handleWith[P any](a Action[P], h func(b *BotHandler, c telebot.Context, payload P) error) route {
+	return route{
+		action:  a.Name,
+		version: a.Version,
+		handle: func(b *BotHandler, c telebot.Context, payload string) error {
+			p, err := a.Codec.Decode(payload)
+			if err != nil {
+				return fmt.Errorf("%w: %s", errStaleCallback, err)
+			}
+			return h(b, c, p)
+		},
+	}
+}
+
+// Router dispatches callback queries to the handlers of their actions.
+type Router struct {
+	routes map[string]route
+}
+
+func NewRouter(routes ...route) *Router {
+
+	r := &Router{routes: make(map[string]route, len(routes))}
+	for _, rt := range routes {
+		if _, ok := r.routes[rt.action]; ok {
+			panic("telegram: duplicate callback action " + rt.action)
+		}
+		r.routes[rt.action] = rt
+	}
+
+	return r
+}
+
+// Dispatch runs the handler of the callback's action. Data of an unknown action, of another version
+// or with a payload that can't be decoded is answered with "кнопка устарела".
+func (r *Router) Dispatch(b *BotHandler, c telebot.Context) error {
+
+	err := errStaleCallback
+
+	parts := strings.SplitN(c.Data(), callbackSep, 3)
+	if len(parts) == 3 {
+		if rt, ok := r.routes[parts[0]]; ok && strconv.Itoa(rt.version) == parts[1] {
+			err = rt.handle(b, c, parts[2])
+		}
+	}
+
+	if errors.Is(err, errStaleCallback) {
+		slog.Info("stale callback data", slog.String("data", c.Data()), slog.Any("err", err))
+		return b.staleButton(c)
+	}
+
+	return err
+}
+
+// staleButton tells the user that the pressed button no longer does anything and shows the current menu.
+func (b *BotHandler) staleButton(c telebot.Context) error {
+	return c.Edit("Кнопка устарела, начните заново.", b.currentKeyboard(c))
+}
@@ -0,0 +1,301 @@
+package telegram
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"errors"
+	"fmt"
+	"gopkg.in/telebot.v3"
+	"log/slog"
+	"strconv"
+)
+
+func (b *BotHandler) MyExercisesHandler(c telebot.Context) error {
+
+	ctx := requestContext(c)
+	active, archived, err := b.Service.Catalog(ctx, c.Sender().ID)
+	if err != nil {
+		slog.Error("catalog error:", slog.Any("err", err))
+		return err
+	}
+
+	if len(active) == 0 && len(archived) == 0 {
+		return c.Edit("У вас пока нет упражнений.", CatalogKeyboard(nil, nil))
+	}
+
+	return c.Edit("Ваши упражнения. 📦 — в архиве, в выборе упражнения их нет.", CatalogKeyboard(active, archived))
+}
+
+func (b *BotHandler) ExerciseMenuHandler(c telebot.Context, exerciseID int64) error {
+
+	exercise, ok, err := b.exercise(c, exerciseID)
+	if !ok {
+		return err
+	}
+
+	return c.Edit(fmt.Sprintf("Упражнение '%s'", exercise.Name), ExerciseMenuKeyboard(exercise))
+}
+
+func (b *BotHandler) RenameExercisePromptHandler(c telebot.Context, exerciseID int64) error {
+
+	exercise, ok, err := b.exercise(c, exerciseID)
+	if !ok {
+		return err
+	}
+
+	ctx := requestContext(c)
+	err = b.Dialogs.Transition(ctx, c.Sender().ID, StateAwaitingNewName, map[string]string{
+		dataExercise: strconv.FormatInt(exercise.Exercise_id, 10),
+	})
+	if err != nil {
+		return c.Send("Сначала завершите текущий ввод или отмените его.", CancelKeyboard())
+	}
+
+	return c.Send(fmt.Sprintf("Введите новое название для '%s'. Все записанные сэты тоже будут переименованы.", exercise.Name), CancelKeyboard())
+}
+
+func (b *BotHandler) RenameExerciseHandler(c telebot.Context, dialog Dialog) error {
+
+	ctx := requestContext(c)
+	exercise, err := b.Service.Exercise(ctx, c.Sender().ID, parseDialogID(dialog.Data[dataExercise]))
+	oldName := exercise.Name
+	if err == nil {
+		exercise.Name, err = b.Service.RenameExercise(ctx, c.Sender().ID, exercise.Exercise_id, c.Message().Text)
+	}
+	if text, ok := exerciseNameErrorText(err); ok {
+		return c.Send(text+" Введите новое название", CancelKeyboard())
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrExerciseExists):
+		return c.Send("Упражнение уже есть. Введите другое название или объедините упражнения.", CancelKeyboard())
+	case errors.Is(err, domain.ErrUnknownExercise):
+		b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil)
+		return c.Send("Этого упражнения больше нет.", b.currentKeyboard(c))
+	case err != nil:
+		slog.Error("rename exercise error:", slog.Any("err", err))
+		return err
+	}
+
+	b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil)
+
+	return c.Send(fmt.Sprintf("Упражнение '%s' переименовано в '%s'.", oldName, exercise.Name), ExerciseMenuKeyboard(exercise))
+}
+
+func (b *BotHandler) ArchiveExerciseHandler(c telebot.Context, exerciseID int64) error {
+	return b.setArchived(c, exerciseID, true)
+}
+
+func (b *BotHandler) RestoreExerciseHandler(c telebot.Context, exerciseID int64) error {
+	return b.setArchived(c, exerciseID, false)
+}
+
+func (b *BotHandler) setArchived(c telebot.Context, exerciseID int64, archive bool) error {
+
+	exercise, ok, err := b.exercise(c, exerciseID)
+	if !ok {
+		return err
+	}
+
+	ctx := requestContext(c)
+
+	var text string
+	if archive {
+		text = fmt.Sprintf("Упражнение '%s' перенесено в архив.", exercise.Name)
+		err = b.Service.ArchiveExercise(ctx, c.Sender().ID, exerciseID)
+	} else {
+		text = fmt.Sprintf("Упражнение '%s' возвращено из архива.", exercise.Name)
+		err = b.Service.RestoreExercise(ctx, c.Sender().ID, exerciseID)
+	}
+
+	if errors.Is(err, domain.ErrUnknownExercise) {
+		return b.MyExercisesHandler(c)
+	}
+	if err != nil {
+		slog.Error("archive exercise error:", slog.Any("err", err))
+		return err
+	}
+
+	return b.editExerciseMenu(c, exerciseID, text)
+}
+
+func (b *BotHandler) FavoriteExerciseHandler(c telebot.Context, exerciseID int64) error {
+	return b.setFavorite(c, exerciseID, true)
+}
+
+func (b *BotHandler) UnfavoriteExerciseHandler(c telebot.Context, exerciseID int64) error {
+	return b.setFavorite(c, exerciseID, false)
+}
+
+func (b *BotHandler) setFavorite(c telebot.Context, exerciseID int64, favorite bool) error {
+
+	exercise, ok, err := b.exercise(c, exerciseID)
+	if !ok {
+		return err
+	}
+
+	ctx := requestContext(c)
+
+	var text string
+	if favorite {
+		text = fmt.Sprintf("Упражнение '%s' добавлено в избранное, оно будет в начале списка.", exercise.Name)
+		err = b.Service.FavoriteExercise(ctx, c.Sender().ID, exerciseID)
+	} else {
+		text = fmt.Sprintf("Упражнение '%s' убрано из избранного.", exercise.Name)
+		err = b.Service.UnfavoriteExercise(ctx, c.Sender().ID, exerciseID)
+	}
+
+	if errors.Is(err, domain.ErrUnknownExercise) {
+		return b.MyExercisesHandler(c)
+	}
+	if err != nil {
+		slog.Error("favorite exercise error:", slog.Any("err", err))
+		return err
+	}
+
+	return b.editExerciseMenu(c, exerciseID, text)
+}
+
+func (b *BotHandler) DeleteExerciseHandler(c telebot.Context, exerciseID int64) error {
+
+	exercise, ok, err := b.exercise(c, exerciseID)
+	if !ok {
+		return err
+	}
+
+	ctx := requestContext(c)
+	err = b.Service.DeleteExercise(ctx, c.Sender().ID, exerciseID)
+	switch {
+	case errors.Is(err, domain.ErrExerciseInUse):
+		return b.editExerciseMenu(c, exerciseID,
+			fmt.Sprintf("У '%s' есть записанные сэты, удалить его нельзя. Его можно перенести в архив или объединить с другим.", exercise.Name))
+	case errors.Is(err, domain.ErrExerciseChosen):
+		return b.editExerciseMenu(c, exerciseID,
+			fmt.Sprintf("'%s' выбрано для текущего сэта. Сначала запишите сэт или отмените выбор.", exercise.Name))
+	case errors.Is(err, domain.ErrUnknownExercise):
+		return b.MyExercisesHandler(c)
+	case err != nil:
+		slog.Error("delete exercise error:", slog.Any("err", err))
+		return err
+	}
+
+	active, archived, err := b.Service.Catalog(ctx, c.Sender().ID)
+	if err != nil {
+		slog.Error("catalog error:", slog.Any("err", err))
+		return err
+	}
+
+	return c.Edit(fmt.Sprintf("Упражнение '%s' удалено.", exercise.Name), CatalogKeyboard(active, archived))
+}
+
+func (b *BotHandler) MergePromptHandler(c telebot.Context, exerciseID int64) error {
+
+	exercise, ok, err := b.exercise(c, exerciseID)
+	if !ok {
+		return err
+	}
+
+	ctx := requestContext(c)
+	active, archived, err := b.Service.Catalog(ctx, c.Sender().ID)
+	if err != nil {
+		slog.Error("catalog error:", slog.Any("err", err))
+		return err
+	}
+
+	var targets []domain.Exercise
+	for _, e := range append(active, archived...) {
+		if e.Exercise_id != exerciseID {
+			targets = append(targets, e)
+		}
+	}
+
+	if len(targets) == 0 {
+		return b.editExerciseMenu(c, exerciseID, "Других упражнений нет, объединять не с чем.")
+	}
+
+	err = b.Dialogs.Transition(ctx, c.Sender().ID, StateAwaitingMergeTarget, map[string]string{
+		dataExercise: strconv.FormatInt(exerciseID, 10),
+	})
+	if err != nil {
+		return c.Send("Сначала завершите текущий ввод или отмените его.", CancelKeyboard())
+	}
+
+	return c.Edit(fmt.Sprintf("С каким упражнением объединить '%s'? Его сэты перейдут в выбранное упражнение, а само оно будет удалено.", exercise.Name),
+		MergeTargetKeyboard(targets))
+}
+
+func (b *BotHandler) MergeExerciseHandler(c telebot.Context, intoID int64) error {
+
+	ctx := requestContext(c)
+	dialog, err := b.Dialogs.Current(ctx, c.Sender().ID)
+	if err != nil && !errors.Is(err, ErrDialogExpired) {
+		slog.Error("load dialog error:", slog.Any("err", err))
+		return err
+	}
+
+	if dialog.State != StateAwaitingMergeTarget {
+		return b.staleButton(c)
+	}
+
+	b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil)
+
+	exercise, ok, err := b.exercise(c, parseDialogID(dialog.Data[dataExercise]))
+	if !ok {
+		return err
+	}
+
+	into, ok, err := b.exercise(c, intoID)
+	if !ok {
+		return err
+	}
+
+	err = b.Service.MergeExercise(ctx, c.Sender().ID, exercise.Exercise_id, into.Exercise_id)
+	switch {
+	case errors.Is(err, domain.ErrUnknownExercise), errors.Is(err, domain.ErrMergeIntoItself):
+		return b.MyExercisesHandler(c)
+	case err != nil:
+		slog.Error("merge exercise error:", slog.Any("err", err))
+		return err
+	}
+
+	return b.editExerciseMenu(c, into.Exercise_id, fmt.Sprintf("Упражнение '%s' объединено с '%s'.", exercise.Name, into.Name))
+}
+
+// editExerciseMenu shows the management menu of the exercise, or the catalog if the exercise is gone.
+func (b *BotHandler) editExerciseMenu(c telebot.Context, exerciseID int64, text string) error {
+
+	exercise, ok, err := b.exercise(c, exerciseID)
+	if !ok {
+		return err
+	}
+
+	return c.Edit(text, ExerciseMenuKeyboard(exercise))
+}
+
+// exercise loads the sender's exercise. If it is not theirs or no longer exists, the catalog is shown
+// instead and ok is false.
+func (b *BotHandler) exercise(c telebot.Context, exerciseID int64) (domain.Exercise, bool, error) {
+
+	ctx := requestContext(c)
+	exercise, err := b.Service.Exercise(ctx, c.Sender().ID, exerciseID)
+	if errors.Is(err, domain.ErrUnknownExercise) {
+		return domain.Exercise{}, false, b.MyExercisesHandler(c)
+	}
+	if err != nil {
+		slog.Error("get exercise error:", slog.Any("err", err))
+		return domain.Exercise{}, false, err
+	}
+
+	return exercise, true, nil
+}
+
+// parseDialogID parses an exercise, set or training id kept in the dialog data. Malformed data gives 0,
+// which nothing has, so it ends up as an unknown exercise, set or training like any id the user doesn't own.
+func parseDialogID(s string) int64 {
+
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return id
+}
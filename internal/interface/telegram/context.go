@@ -0,0 +1,34 @@
+package telegram
+
+import (
+	"context"
+
+	"gopkg.in/telebot.v3"
+)
+
+const contextKey = "ctx"
+
+// WithContext is a middleware that gives every update its own context. The context is derived from
+// the bot's lifetime context, so it is cancelled on shutdown, and is limited by the operation timeout.
+// The timeout is a budget for the whole update, not for each query: a handler that finishes a set,
+// then loads the stats and renders the reply spends one deadline on all of it, so a slow first
+// query leaves less time for the rest.
+func (b *BotHandler) WithContext(next telebot.HandlerFunc) telebot.HandlerFunc {
+	return func(c telebot.Context) error {
+		ctx, cancel := context.WithTimeout(b.ctx, b.timeout)
+		defer cancel()
+
+		c.Set(contextKey, ctx)
+
+		return next(c)
+	}
+}
+
+// requestContext returns the context of the update set by WithContext.
+func requestContext(c telebot.Context) context.Context {
+	if ctx, ok := c.Get(contextKey).(context.Context); ok {
+		return ctx
+	}
+
+	return context.Background()
+}
@@ -0,0 +1,187 @@
+package telegram
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"GymBot/internal/domain/repository"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DialogState is the step of the conversation a chat is currently in, i.e. what the next text message means.
+type DialogState string
+
+const (
+	StateIdle                 DialogState = "idle"
+	StateAwaitingExerciseName DialogState = "awaiting_exercise_name"
+	StateAwaitingWeight       DialogState = "awaiting_weight"
+	StateAwaitingReps         DialogState = "awaiting_reps"
+	StateAwaitingNewName      DialogState = "awaiting_new_exercise_name"
+	StateAwaitingMergeTarget  DialogState = "awaiting_merge_target"
+	StateAwaitingSearch       DialogState = "awaiting_exercise_search"
+	StateAwaitingSetEntry     DialogState = "awaiting_set_entry"
+	StateAwaitingSetWeight    DialogState = "awaiting_set_weight"
+	StateAwaitingSetReps      DialogState = "awaiting_set_reps"
+	StateAwaitingSetExercise  DialogState = "awaiting_set_exercise"
+	StateAwaitingPastStart    DialogState = "awaiting_past_training_start"
+	StateAwaitingPastDuration DialogState = "awaiting_past_training_duration"
+	StateAwaitingPastSets     DialogState = "awaiting_past_training_sets"
+)
+
+// Keys of the dialog context.
+const (
+	dataSetEnd   = "set_end"
+	dataWeight   = "weight"
+	dataExercise = "exercise"
+	dataSet      = "set"
+	dataDate     = "date"
+	dataStart    = "start"
+	dataDuration = "duration"
+	dataPastSets = "past_sets"
+	dataPastSeq  = "past_seq"
+)
+
+const dialogTimeout = 10 * time.Minute
+
+var (
+	ErrInvalidTransition = errors.New("invalid dialog transition")
+	ErrDialogExpired     = errors.New("dialog expired")
+)
+
+// transitions lists the states every state may move to. Moving to StateIdle is always allowed.
+var transitions = map[DialogState][]DialogState{
+	StateIdle:                 {StateAwaitingExerciseName, StateAwaitingWeight, StateAwaitingNewName, StateAwaitingMergeTarget, StateAwaitingSearch, StateAwaitingSetEntry, StateAwaitingSetWeight, StateAwaitingSetReps, StateAwaitingSetExercise, StateAwaitingPastStart},
+	StateAwaitingExerciseName: {},
+	StateAwaitingWeight:       {StateAwaitingReps},
+	StateAwaitingReps:         {},
+	StateAwaitingNewName:      {},
+	StateAwaitingMergeTarget:  {},
+	StateAwaitingSearch:       {},
+	StateAwaitingSetEntry:     {},
+	StateAwaitingSetWeight:    {},
+	StateAwaitingSetReps:      {},
+	StateAwaitingSetExercise:  {},
+	StateAwaitingPastStart:    {StateAwaitingPastDuration},
+	StateAwaitingPastDuration: {StateAwaitingPastSets},
+	StateAwaitingPastSets:     {StateAwaitingPastSets},
+}
+
+// Dialog is the current step of a user's conversation together with the data collected so far.
+type Dialog struct {
+	State DialogState
+	Data  map[string]string
+}
+
+// DialogManager keeps a dialog state per user so that text input of one user never affects another one.
+// Dialogs are stored in a repository and loaded on every update, so pending prompts survive a restart.
+type DialogManager struct {
+	mu   sync.Mutex
+	repo repository.DialogRepository
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+func NewDialogManager(repo repository.DialogRepository, ttl time.Duration) *DialogManager {
+	return &DialogManager{
+		repo: repo,
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+// Current returns the user's dialog. A pending prompt that was not answered in time
+// is dropped and reported with ErrDialogExpired.
+func (m *DialogManager) Current(ctx context.Context, userID int64) (Dialog, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d, err := m.repo.GetDialog(ctx, userID)
+	if err != nil {
+		return Dialog{State: StateIdle}, err
+	}
+
+	if d == nil {
+		return Dialog{State: StateIdle}, nil
+	}
+
+	if m.now().After(d.ExpiresAt) {
+		if err := m.repo.DeleteDialog(ctx, userID); err != nil {
+			return Dialog{State: StateIdle}, err
+		}
+		return Dialog{State: StateIdle}, ErrDialogExpired
+	}
+
+	return Dialog{State: DialogState(d.State), Data: d.Data}, nil
+}
+
+// Transition moves the user's dialog to the given state and restarts its timeout.
+// The given data is merged into the data collected on previous steps.
+func (m *DialogManager) Transition(ctx context.Context, userID int64, to DialogState, data map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if to == StateIdle {
+		return m.repo.DeleteDialog(ctx, userID)
+	}
+
+	d, err := m.repo.GetDialog(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	from := StateIdle
+	merged := make(map[string]string)
+	if d != nil && !m.now().After(d.ExpiresAt) {
+		from = DialogState(d.State)
+		for k, v := range d.Data {
+			merged[k] = v
+		}
+	}
+
+	if !canTransition(from, to) {
+		return ErrInvalidTransition
+	}
+
+	for k, v := range data {
+		merged[k] = v
+	}
+
+	return m.repo.SaveDialog(ctx, domain.Dialog{
+		User_id:   userID,
+		State:     string(to),
+		Data:      merged,
+		ExpiresAt: m.now().Add(m.ttl),
+	})
+}
+
+// Cancel drops the pending prompt of the user and reports whether there was one.
+func (m *DialogManager) Cancel(ctx context.Context, userID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d, err := m.repo.GetDialog(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	if d == nil {
+		return false, nil
+	}
+
+	if err := m.repo.DeleteDialog(ctx, userID); err != nil {
+		return false, err
+	}
+
+	return !m.now().After(d.ExpiresAt), nil
+}
+
+func canTransition(from, to DialogState) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+
+	return false
+}
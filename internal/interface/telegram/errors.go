@@ -0,0 +1,58 @@
+package telegram
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"errors"
+	"fmt"
+
+	"gopkg.in/telebot.v3"
+)
+
+// domainErrorReplies are the messages shown to the user when a use case rejects the action.
+var domainErrorReplies = []struct {
+	err      error
+	text     string
+	keyboard func() *telebot.ReplyMarkup
+}{
+	{domain.ErrTrainingAlreadyActive, "Тренировка уже идет!", TrainingKeyboard},
+	{domain.ErrNoActiveTraining, "Сначала начните тренировку.", StartKeyboard},
+	{domain.ErrNoOpenSet, "Сэт еще не начат.", TrainingKeyboard},
+	{domain.ErrSetAlreadyOpen, "Сначала завершите или отмените текущий сэт.", SetKeyboard},
+	{domain.ErrExerciseNotChosen, "Для начала сэта выберите упражнение.", ChooseKeyboard},
+}
+
+// replyDomainError answers the user with a friendly message if err is a known domain error
+// and reports whether it did.
+func replyDomainError(c telebot.Context, err error) (bool, error) {
+
+	for _, r := range domainErrorReplies {
+		if !errors.Is(err, r.err) {
+			continue
+		}
+
+		if c.Callback() != nil {
+			return true, c.Edit(r.text, r.keyboard())
+		}
+
+		return true, c.Send(r.text, r.keyboard())
+	}
+
+	return false, nil
+}
+
+// exerciseNameErrorText explains why the entered exercise name was rejected.
+func exerciseNameErrorText(err error) (string, bool) {
+
+	switch {
+	case errors.Is(err, domain.ErrEmptyExerciseName):
+		return "Название упражнения не может быть пустым.", true
+	case errors.Is(err, domain.ErrInvalidExerciseName):
+		return "Название должно содержать буквы или цифры и не может начинаться с '/'.", true
+	case errors.Is(err, domain.ErrExerciseNameTooShort):
+		return fmt.Sprintf("Название слишком короткое, нужно хотя бы %d символа.", domain.MinExerciseNameLength), true
+	case errors.Is(err, domain.ErrExerciseNameTooLong):
+		return fmt.Sprintf("Название слишком длинное, максимум %d символов.", domain.MaxExerciseNameLength), true
+	}
+
+	return "", false
+}
@@ -2,6 +2,10 @@ package telegram
 
 import (
 	"GymBot/internal/application"
+	domain "GymBot/internal/domain/entity"
+	"GymBot/internal/domain/repository"
+	"context"
+	"errors"
 	"fmt"
 	"gopkg.in/telebot.v3"
 	"log/slog"
@@ -9,10 +13,11 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
-	weightRegex = `^([1-9]\d*|0)(\.\d+)?([eE][+-]?\d+)?$`
+	weightRegex = `^([1-9]\d*|0)(\.\d+)?$`
 	repsRegex   = `^(0|[1-9]\d*)$`
 )
 
@@ -23,123 +28,174 @@ var (
 
 type BotHandler struct {
 	Service *application.Service
+	Dialogs *DialogManager
+
+	ctx     context.Context
+	timeout time.Duration
 }
 
-func NewBotHandler(service *application.Service) *BotHandler {
+// NewBotHandler creates the handler. ctx lives as long as the bot and timeout limits the handling of one update,
+// every service call of the update included.
+func NewBotHandler(ctx context.Context, service *application.Service, dialogs repository.DialogRepository, timeout time.Duration) *BotHandler {
 	return &BotHandler{
 		Service: service,
+		Dialogs: NewDialogManager(dialogs, dialogTimeout),
+		ctx:     ctx,
+		timeout: timeout,
 	}
 }
 
 func (b *BotHandler) MsgMainHandler(c telebot.Context) error {
+	ctx := requestContext(c)
+
 	msg := c.Message().Text
 
 	switch msg {
 	case "/start":
+		if _, err := b.Dialogs.Cancel(ctx, c.Sender().ID); err != nil {
+			slog.Error("cancel dialog error:", slog.Any("err", err))
+		}
+		return b.StartHandler(c)
+	case "/cancel":
+		return b.CancelHandler(c)
+	}
 
-		b.StartHandler(c)
-	default:
+	dialog, err := b.Dialogs.Current(ctx, c.Sender().ID)
+	if errors.Is(err, ErrDialogExpired) {
+		return c.Send("Время ожидания ввода истекло, начните заново.", b.currentKeyboard(c))
+	}
+	if err != nil {
+		slog.Error("load dialog error:", slog.Any("err", err))
+		return err
+	}
 
+	switch dialog.State {
+	case StateAwaitingExerciseName:
+		return b.AddExerciseHandler(c)
+	case StateAwaitingWeight:
+		return b.WeightHandler(c)
+	case StateAwaitingReps:
+		return b.RepsHandler(c, dialog)
+	case StateAwaitingNewName:
+		return b.RenameExerciseHandler(c, dialog)
+	case StateAwaitingSearch:
+		return b.SearchExerciseHandler(c)
+	case StateAwaitingSetEntry:
+		return b.RecordSetHandler(c)
+	case StateAwaitingSetWeight:
+		return b.SetWeightHandler(c, dialog)
+	case StateAwaitingSetReps:
+		return b.SetRepsHandler(c, dialog)
+	case StateAwaitingSetExercise:
+		return b.SetExerciseHandler(c, dialog)
+	case StateAwaitingPastStart:
+		return b.PastStartHandler(c, dialog)
+	case StateAwaitingPastDuration:
+		return b.PastDurationHandler(c, dialog)
+	case StateAwaitingPastSets:
+		return b.PastSetHandler(c, dialog)
+	case StateAwaitingMergeTarget:
+		return c.Send("Выберите упражнение кнопкой выше или отмените объединение.", CancelKeyboard())
+	default:
+		if entries := parseQuickEntry(msg); len(entries) > 0 {
+			return b.QuickLogHandler(c, entries)
+		}
 		c.Send("Неизвестная команда", StartKeyboard())
 	}
 
 	return nil
 }
 
+// DataHandler handles the inline buttons, see callbackRouter.
 func (b *BotHandler) DataHandler(c telebot.Context) error {
 
-	var err error
-	data := c.Data()
+	if err := callbackRouter.Dispatch(b, c); err != nil {
+		slog.Error("Error in Data Handler:", slog.Any("err", err))
+		return err
+	}
 
-	switch {
-	case strings.HasPrefix(data, "next_"):
+	return nil
+}
 
-		page, err := strconv.Atoi(strings.TrimPrefix(data, "next_"))
-		if err != nil {
-			slog.Error("strconv err:", err)
-		}
+func (b *BotHandler) MainMenuHandler(c telebot.Context) error {
+	return c.Edit("Главное меню", b.currentKeyboard(c))
+}
 
-		c.Edit("Выберите упражнение", b.PagKeyboard(c.Sender().ID, int64(page)))
+func (b *BotHandler) AddExercisePromptHandler(c telebot.Context) error {
 
-	case strings.HasPrefix(data, "prev_"):
+	ctx := requestContext(c)
+	if err := b.Dialogs.Transition(ctx, c.Sender().ID, StateAwaitingExerciseName, nil); err != nil {
+		return c.Send("Сначала завершите текущий ввод или отмените его.", CancelKeyboard())
+	}
 
-		page, err := strconv.Atoi(strings.TrimPrefix(data, "prev_"))
-		if err != nil {
-			slog.Error("strconv err:", err)
-		}
+	return c.Send("Введите упражнение", CancelKeyboard())
+}
 
-		c.Edit("Выберите упражнение", b.PagKeyboard(c.Sender().ID, int64(page)))
+func (b *BotHandler) ExercisePickerHandler(c telebot.Context) error {
+	return b.ExercisePageHandler(c, pickerPage{Order: domain.OrderAdded, Page: 1})
+}
 
-	case strings.HasPrefix(data, "exercise_"):
+func (b *BotHandler) ExercisePageHandler(c telebot.Context, page pickerPage) error {
+	return ignoreNotModified(b.editExercisePicker(c, "Выберите упражнение", page))
+}
 
-		exercise := strings.TrimPrefix(data, "exercise_")
+func (b *BotHandler) editExercisePicker(c telebot.Context, text string, page pickerPage) error {
 
-		err := b.Service.Repo.SetExercise(c.Sender().ID, exercise)
-		if err != nil {
-			slog.Error("Set exercise:", err)
-		}
+	ctx := requestContext(c)
+	keyboard, err := b.exercisePicker(c.Sender().ID, page.Order).Keyboard(ctx, page.Page,
+		orderRow(page.Order), []telebot.InlineButton{btnSearch})
+	if err != nil {
+		slog.Error("exercise page error:", slog.Any("err", err))
+		return err
+	}
 
-		c.Edit("Упражнение выбрано! Можете начинать!", TrainingKeyboardWithExerciseChosen())
+	return c.Edit(text, keyboard)
+}
 
-	default:
-		switch data {
-		case "start_training":
-			err = b.StartTrainingHandler(c)
-		case "add_exercise":
-			c.Send("Введите упражнение")
-			c.Bot().Handle(telebot.OnText, b.AddExerciseHandler)
-		case "end_training":
-			err = b.EndTrainingHandler(c)
-		case "start_set":
-			err = b.StartSetHandler(c)
-		case "end_set":
-			err = b.EndSetHandler(c)
-		case "choose_exercise":
-			err = c.Edit("Выберите упражнение", b.PagKeyboard(c.Sender().ID, 1))
-		case "show_stats":
-			err = b.StatsHandler(c)
+func (b *BotHandler) ChooseExerciseHandler(c telebot.Context, exerciseID int64) error {
 
-		}
+	ctx := requestContext(c)
+	_, err := b.Service.ChooseExercise(ctx, c.Sender().ID, exerciseID)
+	if errors.Is(err, domain.ErrUnknownExercise) {
+		return b.editExercisePicker(c, "Такого упражнения нет. Выберите упражнение", pickerPage{Order: domain.OrderAdded, Page: 1})
+	}
+	if handled, err := replyDomainError(c, err); handled {
+		return err
 	}
-
 	if err != nil {
-		slog.Error("Error in Data Handler:", err)
+		slog.Error("Set exercise:", slog.Any("err", err))
 		return err
 	}
 
-	return nil
+	return c.Edit("Упражнение выбрано! Можете начинать!", TrainingKeyboardWithExerciseChosen())
 }
 
 func (b *BotHandler) StartHandler(c telebot.Context) error {
 
-	Exsist, err := b.Service.Repo.UserCheck(c.Sender().ID)
+	ctx := requestContext(c)
+	isNew, err := b.Service.EnsureUser(ctx, c.Sender().ID)
 	if err != nil {
-		slog.Error("User check error:", err)
+		slog.Error("User registration err:", slog.Any("err", err))
+		return err
 	}
 
-	if !Exsist {
-		err = b.Service.Repo.RegisterUser(c.Sender().ID)
-
-		if err != nil {
-			slog.Error("User registration err:", err)
-			return err
-		}
-
+	if isNew {
 		c.Send("Привет! Бот позволяет тебе добавлять упраженияи, трекать тренировку и получать статистику", StartKeyboard())
-
-	} else if Exsist {
-		c.Send("Давно не виделись!", StartKeyboard())
 	} else {
-		c.Send("Неизвестная команда", StartKeyboard())
+		c.Send("Давно не виделись!", StartKeyboard())
 	}
 	return nil
 }
 
 func (b *BotHandler) StartTrainingHandler(c telebot.Context) error {
 
-	err := b.Service.Repo.StartTrainig(c.Sender().ID, c.Message().Time())
+	ctx := requestContext(c)
+	err := b.Service.StartTraining(ctx, c.Sender().ID, c.Message().Time())
+	if handled, err := replyDomainError(c, err); handled {
+		return err
+	}
 	if err != nil {
-		slog.Error("Start training error:", err)
+		slog.Error("Start training error:", slog.Any("err", err))
 		return err
 	}
 
@@ -151,12 +207,21 @@ func (b *BotHandler) StartTrainingHandler(c telebot.Context) error {
 
 func (b *BotHandler) EndTrainingHandler(c telebot.Context) error {
 
-	err := b.Service.Repo.EndTraining(c.Sender().ID, c.Message().Time())
+	ctx := requestContext(c)
+	err := b.Service.EndTraining(ctx, c.Sender().ID, c.Message().Time())
+	if handled, err := replyDomainError(c, err); handled {
+		return err
+	}
 	if err != nil {
-		slog.Error("End training error:", err)
+		slog.Error("End training error:", slog.Any("err", err))
 		return err
 	}
 
+	// An unfinished set is dropped with the training, so a pending weight/reps prompt is stale
+	if _, err := b.Dialogs.Cancel(ctx, c.Sender().ID); err != nil {
+		slog.Error("cancel dialog error:", slog.Any("err", err))
+	}
+
 	c.Edit("Тренировка завершена!", StartKeyboard())
 
 	return nil
@@ -164,86 +229,159 @@ func (b *BotHandler) EndTrainingHandler(c telebot.Context) error {
 
 func (b *BotHandler) StartSetHandler(c telebot.Context) error {
 
-	isChosen, err := b.Service.Repo.IsExerciseChoosen(c.Sender().ID)
+	ctx := requestContext(c)
+	err := b.Service.StartSet(ctx, c.Sender().ID, c.Message().Time())
+	if handled, err := replyDomainError(c, err); handled {
+		return err
+	}
 	if err != nil {
-		slog.Error("Is exercise choosen error:", err)
+		slog.Error("start set error", slog.Any("err", err))
 		return err
 	}
 
-	if !isChosen {
-		c.Edit("Для начала сэта выберите упражнение.", ChooseKeyboard())
-	} else if isChosen {
-		err = b.Service.Repo.StartSet(c.Sender().ID, c.Message().Time())
-		if err != nil {
-			slog.Error("start set error", err)
-		}
-		c.Edit("Сэт идет!", SetKeyboard())
-
-	}
+	c.Edit("Сэт идет!", SetKeyboard())
 
 	return nil
 }
 
 func (b *BotHandler) EndSetHandler(c telebot.Context) error {
 
-	b.Service.Repo.EndSet(c.Sender().ID, c.Message().Time())
+	ctx := requestContext(c)
+	err := b.Service.RequireOpenSet(ctx, c.Sender().ID)
+	if handled, err := replyDomainError(c, err); handled {
+		return err
+	}
+	if err != nil {
+		slog.Error("require open set error:", slog.Any("err", err))
+		return err
+	}
 
-	c.Send("Сэт завершен! Введите вес, который вы использовали.")
+	err = b.Dialogs.Transition(ctx, c.Sender().ID, StateAwaitingWeight, map[string]string{
+		dataSetEnd: c.Message().Time().Format(time.RFC3339),
+	})
+	if err != nil {
+		return c.Send("Сначала завершите текущий ввод или отмените его.", CancelKeyboard())
+	}
 
-	c.Bot().Handle(telebot.OnText, b.WeightHandler)
+	c.Send("Сэт завершен! Введите вес, который вы использовали.", CancelKeyboard())
 
 	return nil
 }
 
+// CancelSetHandler drops the running set or the chosen exercise, so the user can choose again.
+// CancelSetHandler drops the chosen exercise with its set. Only the prompts of that set are cancelled with it,
+// a rename or a past training being entered goes on.
+func (b *BotHandler) CancelSetHandler(c telebot.Context) error {
+
+	ctx := requestContext(c)
+	dialog, err := b.Dialogs.Current(ctx, c.Sender().ID)
+	if err != nil && !errors.Is(err, ErrDialogExpired) {
+		slog.Error("load dialog error:", slog.Any("err", err))
+		return err
+	}
+
+	set, err := b.Service.CancelSet(ctx, c.Sender().ID)
+	if errors.Is(err, domain.ErrNoOpenSet) {
+		return c.Edit("Нет сэта для отмены.", b.currentKeyboard(c))
+	}
+	if err != nil {
+		slog.Error("cancel set error:", slog.Any("err", err))
+		return err
+	}
+
+	switch dialog.State {
+	case StateAwaitingWeight, StateAwaitingReps, StateAwaitingSetEntry:
+		if err := b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil); err != nil {
+			slog.Error("cancel dialog error:", slog.Any("err", err))
+		}
+	}
+
+	if set.Start.IsZero() {
+		return c.Edit(fmt.Sprintf("Выбор упражнения '%s' отменен.", set.Exercise), TrainingKeyboard())
+	}
+
+	return c.Edit(fmt.Sprintf("Сэт '%s' отменен.", set.Exercise), TrainingKeyboard())
+}
+
 func (b *BotHandler) WeightHandler(c telebot.Context) error {
 
+	ctx := requestContext(c)
 	msg := strings.ReplaceAll(c.Message().Text, ",", ".")
 
 	if weightRegexp.MatchString(msg) {
 
 		weight, err := strconv.ParseFloat(msg, 64)
 		if err != nil {
-			slog.Error("parse float error:", err)
-			c.Send("Ошибка ввода веса. Пожалуйста, введите число c одной цифрой после запятой(точка тож сойдет).")
-			c.Bot().Handle(telebot.OnText, b.WeightHandler)
+			slog.Error("parse float error:", slog.Any("err", err))
+			return c.Send("Ошибка ввода веса. Пожалуйста, введите число c одной цифрой после запятой(точка тож сойдет).")
+		}
+
+		if weight > application.MaxWeight {
+			return c.Send(invalidWeightText)
 		}
 
-		err = b.Service.Repo.SetWeight(c.Sender().ID, weight)
+		err = b.Dialogs.Transition(ctx, c.Sender().ID, StateAwaitingReps, map[string]string{
+			dataWeight: strconv.FormatFloat(weight, 'f', -1, 64),
+		})
 		if err != nil {
-			slog.Error("set weight err:", err)
+			slog.Error("save weight err:", slog.Any("err", err))
 			return err
 		}
 
-		c.Send("Теперь введите количество пfовторений.")
-		c.Bot().Handle(telebot.OnText, b.RepsHandler)
+		c.Send("Теперь введите количество повторений.", CancelKeyboard())
 
 	} else if !weightRegexp.MatchString(msg) {
 
 		c.Send("Ошибка ввода веса. Пожалуйста, введите число c одной цифрой после запятой(точка тож сойдет).")
-
-		c.Bot().Handle(telebot.OnText, b.WeightHandler)
 	}
 
 	return nil
 }
 
-func (b *BotHandler) RepsHandler(c telebot.Context) error {
+// RepsHandler records the set in one go: the end time and weight are taken from the dialog context.
+func (b *BotHandler) RepsHandler(c telebot.Context, dialog Dialog) error {
 
+	ctx := requestContext(c)
 	if repsRegexp.MatchString(c.Message().Text) {
 
 		reps, err := strconv.Atoi(c.Message().Text)
 		if err != nil {
-			c.Send("Ошибка ввода повторений. Пожалуйста, введите целое число.")
-			c.Bot().Handle(telebot.OnText, b.RepsHandler)
+			return c.Send("Ошибка ввода повторений. Пожалуйста, введите целое число.")
+		}
+
+		end, err := time.Parse(time.RFC3339, dialog.Data[dataSetEnd])
+		if err != nil {
+			slog.Error("parse set end error:", slog.Any("err", err))
+			return err
+		}
+
+		weight, err := strconv.ParseFloat(dialog.Data[dataWeight], 64)
+		if err != nil {
+			slog.Error("parse weight error:", slog.Any("err", err))
+			return err
 		}
 
-		b.Service.Repo.SetReps(c.Sender().ID, reps)
-		c.Send("Сэт успешно завершен! Все данные затреканы!", TrainingKeyboard())
+		setID, err := b.Service.FinishSet(ctx, c.Sender().ID, end, weight, reps)
+		if errors.Is(err, domain.ErrInvalidReps) {
+			return c.Send(invalidRepsText)
+		}
+		if errors.Is(err, domain.ErrNoOpenSet) {
+			b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil)
+		}
+		if handled, err := replyDomainError(c, err); handled {
+			return err
+		}
+		if err != nil {
+			slog.Error("finish set error:", slog.Any("err", err))
+			return err
+		}
+
+		b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil)
+		c.Send("Сэт успешно завершен! Все данные затреканы!", RecordedSetKeyboard(setID))
 
 	} else if !repsRegexp.MatchString(c.Message().Text) {
 
 		c.Send("Ошибка ввода повторений. Пожалуйста, введите целое число.")
-		c.Bot().Handle(telebot.OnText, b.RepsHandler)
 
 	}
 
@@ -253,39 +391,70 @@ func (b *BotHandler) RepsHandler(c telebot.Context) error {
 
 func (b *BotHandler) AddExerciseHandler(c telebot.Context) error {
 
-	err := b.Service.Repo.AddExercise(c.Sender().ID, c.Message().Text)
+	ctx := requestContext(c)
+	name, err := b.Service.AddExercise(ctx, c.Sender().ID, c.Message().Text)
+	if text, ok := exerciseNameErrorText(err); ok {
+		return c.Send(text+" Введите упражнение", CancelKeyboard())
+	}
+	if errors.Is(err, domain.ErrExerciseExists) {
+		return c.Send(fmt.Sprintf("Упражнение уже есть: '%s'. Введите другое название", name), CancelKeyboard())
+	}
 	if err != nil {
-		slog.Error("add exercise error:", err)
+		slog.Error("add exercise error:", slog.Any("err", err))
 		return err
 	}
 
-	isActive, err := b.Service.Repo.IsTrainingActive(c.Sender().ID)
+	b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil)
+
+	c.Send(fmt.Sprintf("Упражнение '%s' добавлено.", name), b.currentKeyboard(c))
+
+	return nil
+
+}
+
+func (b *BotHandler) CancelHandler(c telebot.Context) error {
+
+	ctx := requestContext(c)
+	cancelled, err := b.Dialogs.Cancel(ctx, c.Sender().ID)
 	if err != nil {
-		slog.Error("add exercise error:", err)
+		slog.Error("cancel dialog error:", slog.Any("err", err))
 		return err
 	}
 
-	if isActive {
-		c.Send(fmt.Sprintf("Упражнение '%s' добавлено.", c.Message().Text), TrainingKeyboard())
-	} else {
-		c.Send(fmt.Sprintf("Упражнение '%s' добавлено.", c.Message().Text), StartKeyboard())
+	if !cancelled {
+		return c.Send("Нечего отменять.", b.currentKeyboard(c))
 	}
 
-	return nil
+	return c.Send("Ввод отменен.", b.currentKeyboard(c))
+}
+
+func (b *BotHandler) currentKeyboard(c telebot.Context) *telebot.ReplyMarkup {
+
+	ctx := requestContext(c)
+	isActive, err := b.Service.IsTrainingActive(ctx, c.Sender().ID)
+	if err != nil {
+		slog.Error("is training active error:", slog.Any("err", err))
+	}
+
+	if isActive {
+		return TrainingKeyboard()
+	}
 
+	return StartKeyboard()
 }
 
 func (b *BotHandler) StatsHandler(c telebot.Context) error {
 
-	filePath, err := b.Service.Repo.GenerateExelStats(c.Sender().ID, c.Sender().Username)
+	ctx := requestContext(c)
+	filePath, err := b.Service.GenerateExelStats(ctx, c.Sender().ID, c.Sender().Username)
 	if err != nil {
-		slog.Error("generate exel stats error:", err)
+		slog.Error("generate exel stats error:", slog.Any("err", err))
 		return err
 	}
 
 	file, err := os.Open(filePath)
 	if err != nil {
-		slog.Error("open exel stats error:", err)
+		slog.Error("open exel stats error:", slog.Any("err", err))
 		return err
 	}
 
@@ -1,53 +1,128 @@
 package telegram
 
 import (
+	"GymBot/internal/application"
+	domain "GymBot/internal/domain/entity"
+	"context"
 	"fmt"
 	"gopkg.in/telebot.v3"
-	"log/slog"
+	"strconv"
+	"time"
 )
 
+// Actions of the inline buttons. Bump the version of an action when its payload changes.
 var (
-	btnStartTraining = telebot.InlineButton{
-		Text: "Начать тренировку",
-		Data: "start_training",
-	}
+	actStartTraining  = Action[struct{}]{Name: "start_training", Version: 1, Codec: noPayload}
+	actEndTraining    = Action[struct{}]{Name: "end_training", Version: 1, Codec: noPayload}
+	actStartSet       = Action[struct{}]{Name: "start_set", Version: 1, Codec: noPayload}
+	actEndSet         = Action[struct{}]{Name: "end_set", Version: 1, Codec: noPayload}
+	actAddExercise    = Action[struct{}]{Name: "add_exercise", Version: 1, Codec: noPayload}
+	actChooseExercise = Action[struct{}]{Name: "choose_exercise", Version: 1, Codec: noPayload}
+	actStats          = Action[struct{}]{Name: "show_stats", Version: 1, Codec: noPayload}
+	actCancel         = Action[struct{}]{Name: "cancel", Version: 1, Codec: noPayload}
+	actMyExercises    = Action[struct{}]{Name: "my_exercises", Version: 1, Codec: noPayload}
+	actMainMenu       = Action[struct{}]{Name: "main_menu", Version: 1, Codec: noPayload}
+	actSearch         = Action[struct{}]{Name: "search", Version: 1, Codec: noPayload}
+	actRecordSet      = Action[struct{}]{Name: "record_set", Version: 1, Codec: noPayload}
+	actEditSets       = Action[struct{}]{Name: "edit_sets", Version: 1, Codec: noPayload}
+	actCancelSet      = Action[struct{}]{Name: "cancel_set", Version: 1, Codec: noPayload}
+	actPastTraining   = Action[struct{}]{Name: "past_training", Version: 1, Codec: noPayload}
+	actPastDone       = Action[struct{}]{Name: "past_done", Version: 1, Codec: noPayload}
+	actNoop           = Action[struct{}]{Name: "noop", Version: 1, Codec: noPayload}
 
-	btnEndTraining = telebot.InlineButton{
-		Text: "Закончить тренировку",
-		Data: "end_training",
-	}
+	actExercisePage = Action[pickerPage]{Name: "page", Version: 2, Codec: pickerPayload}
+	actExercise     = Action[int64]{Name: "exercise", Version: 1, Codec: idPayload}
+	actUndoSet      = Action[int64]{Name: "undo_set", Version: 2, Codec: idPayload}
+	actUndoLog      = Action[setRange]{Name: "undo_log", Version: 1, Codec: setRangePayload}
+	actUndoPastSets = Action[int64]{Name: "undo_past_sets", Version: 1, Codec: idPayload}
+	actCalendar     = Action[time.Time]{Name: "calendar", Version: 1, Codec: monthPayload}
+	actPastDate     = Action[time.Time]{Name: "past_date", Version: 1, Codec: datePayload}
 
-	btnStartSet = telebot.InlineButton{
-		Text: "Начать сэт",
-		Data: "start_set",
-	}
+	actManage     = Action[int64]{Name: "manage", Version: 1, Codec: idPayload}
+	actRename     = Action[int64]{Name: "rename", Version: 1, Codec: idPayload}
+	actArchive    = Action[int64]{Name: "archive", Version: 1, Codec: idPayload}
+	actRestore    = Action[int64]{Name: "restore", Version: 1, Codec: idPayload}
+	actFavorite   = Action[int64]{Name: "favorite", Version: 1, Codec: idPayload}
+	actUnfavorite = Action[int64]{Name: "unfavorite", Version: 1, Codec: idPayload}
+	actDelete     = Action[int64]{Name: "delete", Version: 1, Codec: idPayload}
+	actMerge      = Action[int64]{Name: "merge", Version: 1, Codec: idPayload}
+	actMergeInto  = Action[int64]{Name: "merge_into", Version: 1, Codec: idPayload}
 
-	btnEndSet = telebot.InlineButton{
-		Text: "Закончить сэт",
-		Data: "end_set",
-	}
+	actSet         = Action[int64]{Name: "set", Version: 1, Codec: idPayload}
+	actSetWeight   = Action[int64]{Name: "set_weight", Version: 1, Codec: idPayload}
+	actSetReps     = Action[int64]{Name: "set_reps", Version: 1, Codec: idPayload}
+	actSetExercise = Action[int64]{Name: "set_exercise", Version: 1, Codec: idPayload}
+	actDeleteSet   = Action[int64]{Name: "delete_set", Version: 1, Codec: idPayload}
+)
 
-	btnAdd = telebot.InlineButton{
-		Text: "Добавить упражнение",
-		Data: "add_exercise",
-	}
+var (
+	btnStartTraining  = actStartTraining.Button("Начать тренировку", struct{}{})
+	btnEndTraining    = actEndTraining.Button("Закончить тренировку", struct{}{})
+	btnStartSet       = actStartSet.Button("Начать сэт", struct{}{})
+	btnEndSet         = actEndSet.Button("Закончить сэт", struct{}{})
+	btnAdd            = actAddExercise.Button("Добавить упражнение", struct{}{})
+	btnChooseExercise = actChooseExercise.Button("Выбрать упражнение", struct{}{})
+	btnStats          = actStats.Button("Показать статистику", struct{}{})
+	btnCancel         = actCancel.Button("Отмена", struct{}{})
+	btnMyExercises    = actMyExercises.Button("Мои упражнения", struct{}{})
+	btnMainMenu       = actMainMenu.Button("Назад", struct{}{})
+	btnSearch         = actSearch.Button("🔍 Поиск", struct{}{})
+	btnRecordSet      = actRecordSet.Button("Записать сэт", struct{}{})
+	btnEditSets       = actEditSets.Button("✏️ Изменить сэты", struct{}{})
+	btnPastTraining   = actPastTraining.Button("Добавить прошедшую тренировку", struct{}{})
+	btnPastDone       = actPastDone.Button("Готово", struct{}{})
+)
 
-	btnChooseExercise = telebot.InlineButton{
-		Text: "Выбрать упражнение",
-		Data: "choose_exercise",
-	}
+// callbackRouter maps every action to its handler.
+var callbackRouter = NewRouter(
+	handle(actStartTraining, (*BotHandler).StartTrainingHandler),
+	handle(actEndTraining, (*BotHandler).EndTrainingHandler),
+	handle(actStartSet, (*BotHandler).StartSetHandler),
+	handle(actEndSet, (*BotHandler).EndSetHandler),
+	handle(actAddExercise, (*BotHandler).AddExercisePromptHandler),
+	handle(actChooseExercise, (*BotHandler).ExercisePickerHandler),
+	handle(actStats, (*BotHandler).StatsHandler),
+	handle(actCancel, (*BotHandler).CancelHandler),
+	handle(actMyExercises, (*BotHandler).MyExercisesHandler),
+	handle(actMainMenu, (*BotHandler).MainMenuHandler),
+	handle(actSearch, (*BotHandler).SearchPromptHandler),
+	handle(actRecordSet, (*BotHandler).RecordSetPromptHandler),
+	handle(actEditSets, (*BotHandler).EditSetsHandler),
+	handle(actCancelSet, (*BotHandler).CancelSetHandler),
+	handle(actPastTraining, (*BotHandler).PastTrainingHandler),
+	handle(actPastDone, (*BotHandler).PastTrainingDoneHandler),
+	handle(actNoop, (*BotHandler).NoopHandler),
 
-	btnStats = telebot.InlineButton{
-		Text: "Показать статистику",
-		Data: "show_stats",
-	}
+	handleWith(actExercisePage, (*BotHandler).ExercisePageHandler),
+	handleWith(actExercise, (*BotHandler).ChooseExerciseHandler),
+	handleWith(actUndoSet, (*BotHandler).UndoSetHandler),
+	handleWith(actUndoLog, (*BotHandler).UndoLogHandler),
+	handleWith(actUndoPastSets, (*BotHandler).UndoPastSetsHandler),
+	handleWith(actCalendar, (*BotHandler).CalendarHandler),
+	handleWith(actPastDate, (*BotHandler).PastDateHandler),
+
+	handleWith(actManage, (*BotHandler).ExerciseMenuHandler),
+	handleWith(actRename, (*BotHandler).RenameExercisePromptHandler),
+	handleWith(actArchive, (*BotHandler).ArchiveExerciseHandler),
+	handleWith(actRestore, (*BotHandler).RestoreExerciseHandler),
+	handleWith(actFavorite, (*BotHandler).FavoriteExerciseHandler),
+	handleWith(actUnfavorite, (*BotHandler).UnfavoriteExerciseHandler),
+	handleWith(actDelete, (*BotHandler).DeleteExerciseHandler),
+	handleWith(actMerge, (*BotHandler).MergePromptHandler),
+	handleWith(actMergeInto, (*BotHandler).MergeExerciseHandler),
+
+	handleWith(actSet, (*BotHandler).SetMenuHandler),
+	handleWith(actSetWeight, (*BotHandler).EditSetWeightPromptHandler),
+	handleWith(actSetReps, (*BotHandler).EditSetRepsPromptHandler),
+	handleWith(actSetExercise, (*BotHandler).EditSetExercisePromptHandler),
+	handleWith(actDeleteSet, (*BotHandler).DeleteSetHandler),
 )
 
 func StartKeyboard() *telebot.ReplyMarkup {
 	return &telebot.ReplyMarkup{
 		InlineKeyboard: [][]telebot.InlineButton{
-			{btnStartTraining}, {btnAdd},
-			{btnStats},
+			{btnStartTraining}, {btnPastTraining},
+			{btnAdd}, {btnMyExercises}, {btnStats},
 		}}
 }
 
@@ -56,21 +131,66 @@ func TrainingKeyboard() *telebot.ReplyMarkup {
 		InlineKeyboard: [][]telebot.InlineButton{
 			{btnStartSet, btnEndTraining},
 			{btnAdd, btnChooseExercise},
+			{btnEditSets},
 		}}
 }
 
+// RecordedSetKeyboard is the training keyboard with a button that undoes the set just recorded.
+func RecordedSetKeyboard(setID int64) *telebot.ReplyMarkup {
+
+	keyboard := TrainingKeyboard()
+	keyboard.InlineKeyboard = append([][]telebot.InlineButton{{actUndoSet.Button("↩️ Отменить сэт", setID)}}, keyboard.InlineKeyboard...)
+
+	return keyboard
+}
+
 func TrainingKeyboardWithExerciseChosen() *telebot.ReplyMarkup {
 	return &telebot.ReplyMarkup{
 		InlineKeyboard: [][]telebot.InlineButton{
 			{btnStartSet, btnEndTraining},
+			{btnRecordSet},
+			{actChooseExercise.Button("Сменить упражнение", struct{}{}), actCancelSet.Button("Отменить выбор", struct{}{})},
 			{btnAdd},
 		}}
 }
 
+// QuickLogKeyboard is the training keyboard with a button that undoes the logged sets.
+func QuickLogKeyboard(logged setRange) *telebot.ReplyMarkup {
+
+	keyboard := TrainingKeyboard()
+	keyboard.InlineKeyboard = append([][]telebot.InlineButton{{undoLogButton(logged)}}, keyboard.InlineKeyboard...)
+
+	return keyboard
+}
+
+// PastSetsKeyboard saves the past training with the entered sets or drops it.
+func PastSetsKeyboard() *telebot.ReplyMarkup {
+	return &telebot.ReplyMarkup{
+		InlineKeyboard: [][]telebot.InlineButton{
+			{btnPastDone},
+			{btnCancel},
+		}}
+}
+
+// PastLogKeyboard is PastSetsKeyboard with a button that undoes the entry with the given number.
+func PastLogKeyboard(entry int64) *telebot.ReplyMarkup {
+	return &telebot.ReplyMarkup{
+		InlineKeyboard: [][]telebot.InlineButton{
+			{actUndoPastSets.Button("↩️ Отменить запись", entry)},
+			{btnPastDone},
+			{btnCancel},
+		}}
+}
+
+func undoLogButton(logged setRange) telebot.InlineButton {
+	return actUndoLog.Button("↩️ Отменить запись", logged)
+}
+
 func SetKeyboard() *telebot.ReplyMarkup {
 	return &telebot.ReplyMarkup{
 		InlineKeyboard: [][]telebot.InlineButton{
 			{btnEndSet},
+			{actCancelSet.Button("Отменить сэт", struct{}{})},
 		}}
 }
 
@@ -82,112 +202,237 @@ func ChooseKeyboard() *telebot.ReplyMarkup {
 	}
 }
 
-func (b *BotHandler) PagKeyboard(id, current_page int64) *telebot.ReplyMarkup {
+// SearchResultKeyboard lists the found exercises, a button chooses the exercise like the picker does.
+func SearchResultKeyboard(found []domain.Exercise) *telebot.ReplyMarkup {
 
-	var (
-		nexBtn = telebot.InlineButton{
-			Text: "Next",
-			Data: fmt.Sprintf("next_%d", current_page+1),
-		}
+	rows := [][]telebot.InlineButton{}
+	for _, exercise := range found {
+		rows = append(rows, []telebot.InlineButton{actExercise.Button(exercise.Name, exercise.Exercise_id)})
+	}
 
-		prevBtn = telebot.InlineButton{
-			Text: "Previous",
-			Data: fmt.Sprintf("prev_%d", current_page-1),
-		}
-	)
+	rows = append(rows, []telebot.InlineButton{btnSearch, btnChooseExercise})
 
-	page, err := b.Service.Repo.GetPage(id, current_page)
-	if err != nil {
-		slog.Error("GetPage err:", err)
+	return &telebot.ReplyMarkup{
+		InlineKeyboard: rows,
 	}
+}
 
-	var exerciseBtns []telebot.InlineButton
-	for _, exercise := range page {
-		exerciseBtns = append(exerciseBtns, telebot.InlineButton{
-			Text: exercise,
-			Data: fmt.Sprintf("exercise_%s", exercise),
-		})
+func CancelKeyboard() *telebot.ReplyMarkup {
+	return &telebot.ReplyMarkup{
+		InlineKeyboard: [][]telebot.InlineButton{
+			{btnCancel},
+		},
 	}
+}
+
+// CatalogKeyboard lists the user's exercises for management, archived ones are marked with 📦.
+func CatalogKeyboard(active, archived []domain.Exercise) *telebot.ReplyMarkup {
 
 	rows := [][]telebot.InlineButton{}
-	for _, btn := range exerciseBtns {
-		rows = append(rows, []telebot.InlineButton{btn})
+	for _, exercise := range active {
+		rows = append(rows, []telebot.InlineButton{actManage.Button(exercise.Name, exercise.Exercise_id)})
 	}
+	for _, exercise := range archived {
+		rows = append(rows, []telebot.InlineButton{actManage.Button("📦 "+exercise.Name, exercise.Exercise_id)})
+	}
+
+	rows = append(rows, []telebot.InlineButton{btnAdd}, []telebot.InlineButton{btnMainMenu})
 
-	maxPage, err := b.Service.Repo.MaxPages(id)
-	if err != nil {
-		slog.Error("GetMaxPages err:", err)
+	return &telebot.ReplyMarkup{
+		InlineKeyboard: rows,
 	}
+}
 
-	if current_page == 1 && current_page != maxPage {
-		rows = append(rows, []telebot.InlineButton{nexBtn})
-	} else if current_page == maxPage && current_page != 1 {
-		rows = append(rows, []telebot.InlineButton{prevBtn})
-	} else if current_page == maxPage && current_page == 1 {
-		slog.Info("Keyboard rows generated", slog.Any("Rows", rows))
-		return &telebot.ReplyMarkup{
-			InlineKeyboard: rows,
-		}
-	} else {
-		rows = append(rows, []telebot.InlineButton{prevBtn, nexBtn})
+func ExerciseMenuKeyboard(exercise domain.Exercise) *telebot.ReplyMarkup {
+
+	id := exercise.Exercise_id
+
+	archiveBtn := actArchive.Button("В архив", id)
+	if exercise.Archived {
+		archiveBtn = actRestore.Button("Вернуть из архива", id)
+	}
+
+	favoriteBtn := actFavorite.Button("⭐ В избранное", id)
+	if exercise.Favorite {
+		favoriteBtn = actUnfavorite.Button("Убрать из избранного", id)
 	}
 
+	return &telebot.ReplyMarkup{
+		InlineKeyboard: [][]telebot.InlineButton{
+			{actRename.Button("Переименовать", id), archiveBtn},
+			{favoriteBtn},
+			{actMerge.Button("Объединить с другим", id)},
+			{actDelete.Button("Удалить", id)},
+			{btnMyExercises},
+		}}
+}
+
+// SetsKeyboard lists the recorded sets of the training, a button opens the set for editing.
+func SetsKeyboard(sets []domain.Set) *telebot.ReplyMarkup {
+
+	rows := [][]telebot.InlineButton{}
+	for i, set := range sets {
+		rows = append(rows, []telebot.InlineButton{actSet.Button(fmt.Sprintf("%d. %s", i+1, formatSet(set)), set.Set_id)})
+	}
+
+	rows = append(rows, []telebot.InlineButton{btnMainMenu})
+
 	return &telebot.ReplyMarkup{
 		InlineKeyboard: rows,
 	}
 }
 
-func (b *BotHandler) StatsPagKeyboard(id, current_page int64) *telebot.ReplyMarkup {
+func SetMenuKeyboard(set domain.Set) *telebot.ReplyMarkup {
 
-	var (
-		nexBtn = telebot.InlineButton{
-			Text: "Next",
-			Data: fmt.Sprintf("next_%d", current_page+1),
-		}
+	id := set.Set_id
+
+	return &telebot.ReplyMarkup{
+		InlineKeyboard: [][]telebot.InlineButton{
+			{actSetWeight.Button("Вес", id), actSetReps.Button("Повторения", id)},
+			{actSetExercise.Button("Упражнение", id)},
+			{actDeleteSet.Button("Удалить", id)},
+			{actEditSets.Button("К списку сэтов", struct{}{})},
+		}}
+}
+
+// MergeTargetKeyboard lists the exercises another exercise can be merged into.
+func MergeTargetKeyboard(targets []domain.Exercise) *telebot.ReplyMarkup {
+
+	rows := [][]telebot.InlineButton{}
+	for _, exercise := range targets {
+		rows = append(rows, []telebot.InlineButton{actMergeInto.Button(exercise.Name, exercise.Exercise_id)})
+	}
+
+	rows = append(rows, []telebot.InlineButton{btnCancel})
+
+	return &telebot.ReplyMarkup{
+		InlineKeyboard: rows,
+	}
+}
+
+// orderLabels are the texts of the picker's sort buttons.
+var orderLabels = map[domain.ExerciseOrder]string{
+	domain.OrderAdded:    "По порядку",
+	domain.OrderName:     "А–Я",
+	domain.OrderMostUsed: "Частые",
+	domain.OrderRecent:   "Недавние",
+}
 
-		prevBtn = telebot.InlineButton{
-			Text: "Previous",
-			Data: fmt.Sprintf("prev_%d", current_page-1),
+// exercisePicker pages through the user's active exercises in the given order, a button chooses the
+// exercise for the next set. The favourites and the exercises of the last training are pinned above
+// the first page.
+func (b *BotHandler) exercisePicker(id int64, order domain.ExerciseOrder) Paginator[domain.Exercise] {
+	return Paginator[domain.Exercise]{
+		Load: func(ctx context.Context, page int64) (application.Page[domain.Exercise], error) {
+			return b.Service.ExercisePage(ctx, id, order, page)
+		},
+		Render: func(exercise domain.Exercise) telebot.InlineButton {
+			return actExercise.Button(exercise.Name, exercise.Exercise_id)
+		},
+		Nav: func(text string, page int64) telebot.InlineButton {
+			return actExercisePage.Button(text, pickerPage{Order: order, Page: page})
+		},
+		Header: func(ctx context.Context, page application.Page[domain.Exercise]) ([][]telebot.InlineButton, error) {
+			if page.Page != 1 {
+				return nil, nil
+			}
+
+			pinned, err := b.Service.PinnedExercises(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+
+			var rows [][]telebot.InlineButton
+			for _, exercise := range pinned {
+				mark := "🕘 "
+				if exercise.Favorite {
+					mark = "⭐ "
+				}
+				rows = append(rows, []telebot.InlineButton{actExercise.Button(mark+exercise.Name, exercise.Exercise_id)})
+			}
+
+			return rows, nil
+		},
+	}
+}
+
+// orderRow switches the picker between the orders, the current one is marked with ✓.
+func orderRow(current domain.ExerciseOrder) []telebot.InlineButton {
+
+	var row []telebot.InlineButton
+	for _, order := range domain.ExerciseOrders {
+		text := orderLabels[order]
+		if order == current {
+			text = "✓ " + text
 		}
-	)
+		row = append(row, actExercisePage.Button(text, pickerPage{Order: order, Page: 1}))
+	}
+
+	return row
+}
 
-	page, err := b.Service.Repo.GetPage(id, current_page)
-	if err != nil {
-		slog.Error("GetPage err:", err)
+var (
+	monthNames   = [...]string{"Январь", "Февраль", "Март", "Апрель", "Май", "Июнь", "Июль", "Август", "Сентябрь", "Октябрь", "Ноябрь", "Декабрь"}
+	weekdayNames = [...]string{"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"}
+)
+
+// CalendarKeyboard shows the month for picking the day of a past training. The weeks start on Monday,
+// days after today can't be picked and there is no way to months after the current one.
+func CalendarKeyboard(month, today time.Time) *telebot.ReplyMarkup {
+
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.Local)
+	last := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local)
+
+	next := noopButton(" ")
+	if !first.AddDate(0, 1, 0).After(last) {
+		next = actCalendar.Button("›", first.AddDate(0, 1, 0))
 	}
 
-	var exerciseBtns []telebot.InlineButton
-	for _, exercise := range page {
-		exerciseBtns = append(exerciseBtns, telebot.InlineButton{
-			Text: exercise,
-			Data: fmt.Sprintf("exercise_%s", exercise),
-		})
+	rows := [][]telebot.InlineButton{{
+		actCalendar.Button("‹", first.AddDate(0, -1, 0)),
+		noopButton(fmt.Sprintf("%s %d", monthNames[first.Month()-1], first.Year())),
+		next,
+	}}
+
+	var week []telebot.InlineButton
+	for _, name := range weekdayNames {
+		week = append(week, noopButton(name))
 	}
+	rows = append(rows, week)
 
-	rows := [][]telebot.InlineButton{}
-	for _, btn := range exerciseBtns {
-		rows = append(rows, []telebot.InlineButton{btn})
+	week = nil
+	for i := 0; i < (int(first.Weekday())+6)%7; i++ {
+		week = append(week, noopButton(" "))
 	}
 
-	maxPage, err := b.Service.Repo.MaxPages(id)
-	if err != nil {
-		slog.Error("GetMaxPages err:", err)
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		if day.After(last) {
+			week = append(week, noopButton("·"))
+		} else {
+			week = append(week, actPastDate.Button(strconv.Itoa(day.Day()), day))
+		}
+
+		if len(week) == len(weekdayNames) {
+			rows = append(rows, week)
+			week = nil
+		}
 	}
 
-	if current_page == 1 && current_page != maxPage {
-		rows = append(rows, []telebot.InlineButton{nexBtn})
-	} else if current_page == maxPage && current_page != 1 {
-		rows = append(rows, []telebot.InlineButton{prevBtn})
-	} else if current_page == maxPage && current_page == 1 {
-		slog.Info("Keyboard rows generated", slog.Any("Rows", rows))
-		return &telebot.ReplyMarkup{
-			InlineKeyboard: rows,
+	if len(week) > 0 {
+		for len(week) < len(weekdayNames) {
+			week = append(week, noopButton(" "))
 		}
-	} else {
-		rows = append(rows, []telebot.InlineButton{prevBtn, nexBtn})
+		rows = append(rows, week)
 	}
 
+	rows = append(rows, []telebot.InlineButton{btnMainMenu})
+
 	return &telebot.ReplyMarkup{
 		InlineKeyboard: rows,
 	}
 }
+
+// noopButton is a button that only shows text, pressing it does nothing.
+func noopButton(text string) telebot.InlineButton {
+	return actNoop.Button(text, struct{}{})
+}
@@ -0,0 +1,83 @@
+package telegram
+
+import (
+	"GymBot/internal/application"
+	"context"
+	"errors"
+	"fmt"
+	"gopkg.in/telebot.v3"
+)
+
+// Paginator renders a page of a list as an inline keyboard: a button per item and a navigation row.
+type Paginator[T any] struct {
+	// Load returns the requested page, clamped to the existing ones.
+	Load func(ctx context.Context, page int64) (application.Page[T], error)
+	// Render makes the button of one item.
+	Render func(item T) telebot.InlineButton
+	// Nav makes a navigation button that opens the page.
+	Nav func(text string, page int64) telebot.InlineButton
+	// Header, if set, returns the rows shown above the items of the loaded page.
+	Header func(ctx context.Context, page application.Page[T]) ([][]telebot.InlineButton, error)
+}
+
+// Keyboard loads the page and appends the extra rows below the navigation row.
+func (p Paginator[T]) Keyboard(ctx context.Context, page int64, extra ...[]telebot.InlineButton) (*telebot.ReplyMarkup, error) {
+
+	loaded, err := p.Load(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := [][]telebot.InlineButton{}
+	if p.Header != nil {
+		header, err := p.Header(ctx, loaded)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, header...)
+	}
+
+	for _, item := range loaded.Items {
+		rows = append(rows, []telebot.InlineButton{p.Render(item)})
+	}
+
+	if nav := p.navRow(loaded.Page, loaded.Pages); len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+
+	return &telebot.ReplyMarkup{
+		InlineKeyboard: append(rows, extra...),
+	}, nil
+}
+
+// navRow is « ‹ X / Y › ». The buttons that would lead out of the list are left out and a list
+// of one page has no navigation at all. Pressing X / Y reloads the current page.
+func (p Paginator[T]) navRow(page, pages int64) []telebot.InlineButton {
+
+	if pages <= 1 {
+		return nil
+	}
+
+	var nav []telebot.InlineButton
+	if page > 1 {
+		nav = append(nav, p.Nav("«", 1), p.Nav("‹", page-1))
+	}
+
+	nav = append(nav, p.Nav(fmt.Sprintf("%d / %d", page, pages), page))
+
+	if page < pages {
+		nav = append(nav, p.Nav("›", page+1), p.Nav("»", pages))
+	}
+
+	return nav
+}
+
+// ignoreNotModified drops the error Telegram returns when a page is reopened without changes.
+func ignoreNotModified(err error) error {
+
+	if errors.Is(err, telebot.ErrSameMessageContent) || errors.Is(err, telebot.ErrMessageNotModified) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,76 @@
+package telegram
+
+import (
+	"GymBot/internal/application"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/telebot.v3"
+)
+
+// testPaginator renders the navigation buttons as "text:page".
+var testPaginator = Paginator[string]{
+	Nav: func(text string, page int64) telebot.InlineButton {
+		return telebot.InlineButton{Text: text, Data: fmt.Sprint(page)}
+	},
+}
+
+func TestNavRow(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		page, pages int64
+		want        string
+	}{
+		{"single page", 1, 1, ""},
+		{"first page", 1, 3, "1 / 3:1 ›:2 »:3"},
+		{"middle page", 2, 3, "«:1 ‹:1 2 / 3:2 ›:3 »:3"},
+		{"last page", 3, 3, "«:1 ‹:2 3 / 3:3"},
+		{"two pages", 2, 2, "«:1 ‹:1 2 / 2:2"},
+	}
+
+	for _, tt := range tests {
+		if got := buttons(testPaginator.navRow(tt.page, tt.pages)); got != tt.want {
+			t.Errorf("%s: navRow(%d, %d) = %q, want %q", tt.name, tt.page, tt.pages, got, tt.want)
+		}
+	}
+}
+
+func TestPaginatorKeyboard(t *testing.T) {
+
+	p := testPaginator
+	p.Load = func(ctx context.Context, page int64) (application.Page[string], error) {
+		return application.Page[string]{Items: []string{"Жим", "Присед"}, Page: page, Pages: 2}, nil
+	}
+	p.Render = func(item string) telebot.InlineButton {
+		return telebot.InlineButton{Text: item, Data: item}
+	}
+
+	extra := []telebot.InlineButton{{Text: "Назад", Data: "back"}}
+	markup, err := p.Keyboard(context.Background(), 1, extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rows []string
+	for _, row := range markup.InlineKeyboard {
+		rows = append(rows, buttons(row))
+	}
+
+	want := []string{"Жим:Жим", "Присед:Присед", "1 / 2:1 ›:2 »:2", "Назад:back"}
+	if strings.Join(rows, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Keyboard rows = %q, want %q", rows, want)
+	}
+}
+
+func buttons(row []telebot.InlineButton) string {
+
+	var s []string
+	for _, b := range row {
+		s = append(s, b.Text+":"+b.Data)
+	}
+
+	return strings.Join(s, " ")
+}
@@ -0,0 +1,371 @@
+package telegram
+
+import (
+	"GymBot/internal/application"
+	domain "GymBot/internal/domain/entity"
+	"context"
+	"errors"
+	"fmt"
+	"gopkg.in/telebot.v3"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A past training is entered step by step: the day in the calendar, the start time, the duration,
+// then the sets in the same form as the quick entry. The times are in the bot's time zone. The sets
+// are kept in the dialog and stored together with the training once the entry is done.
+
+const dateLayout = "2006-01-02"
+
+var (
+	clockRegexp    = regexp.MustCompile(`^([01]?\d|2[0-3])(?:[:.]([0-5]\d))?$`)
+	durationRegexp = regexp.MustCompile(`^(?:(\d{1,3})|(\d{1,2})[:.]([0-5]\d))$`)
+)
+
+// parseClock parses "18:30", "18.30" or just "18".
+func parseClock(text string) (int, int, bool) {
+
+	m := clockRegexp.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil {
+		return 0, 0, false
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+
+	return hour, minute, true
+}
+
+// parseDuration parses minutes, "90", or hours and minutes, "1:30".
+func parseDuration(text string) (time.Duration, bool) {
+
+	m := durationRegexp.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil {
+		return 0, false
+	}
+
+	if m[1] != "" {
+		minutes, _ := strconv.Atoi(m[1])
+		return time.Duration(minutes) * time.Minute, true
+	}
+
+	hours, _ := strconv.Atoi(m[2])
+	minutes, _ := strconv.Atoi(m[3])
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, true
+}
+
+// formatTraining is "18.10.2026 18:30–20:00".
+func formatTraining(training domain.Training) string {
+	return training.Start.Format("02.01.2006 15:04") + "–" + training.End.Format("15:04")
+}
+
+func (b *BotHandler) PastTrainingHandler(c telebot.Context) error {
+	return b.CalendarHandler(c, time.Now())
+}
+
+func (b *BotHandler) CalendarHandler(c telebot.Context, month time.Time) error {
+	return ignoreNotModified(c.Edit("Выберите день тренировки", CalendarKeyboard(month, time.Now())))
+}
+
+// NoopHandler answers the buttons that only show text, like the days of the week in the calendar.
+func (b *BotHandler) NoopHandler(c telebot.Context) error {
+	return c.Respond()
+}
+
+func (b *BotHandler) PastDateHandler(c telebot.Context, date time.Time) error {
+
+	if date.After(time.Now()) {
+		return b.CalendarHandler(c, date)
+	}
+
+	ctx := requestContext(c)
+	err := b.Dialogs.Transition(ctx, c.Sender().ID, StateAwaitingPastStart, map[string]string{
+		dataDate: date.Format(dateLayout),
+	})
+	if err != nil {
+		return c.Send("Сначала завершите текущий ввод или отмените его.", CancelKeyboard())
+	}
+
+	return c.Send(fmt.Sprintf("Тренировка %s. Во сколько она началась? Введите время, например 18:30.", date.Format("02.01.2006")),
+		CancelKeyboard())
+}
+
+func (b *BotHandler) PastStartHandler(c telebot.Context, dialog Dialog) error {
+
+	hour, minute, ok := parseClock(c.Message().Text)
+	if !ok {
+		return c.Send("Не понял время. Введите его так: 18:30.", CancelKeyboard())
+	}
+
+	date, err := time.ParseInLocation(dateLayout, dialog.Data[dataDate], time.Local)
+	if err != nil {
+		slog.Error("parse past training date error:", slog.Any("err", err))
+		return err
+	}
+
+	start := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, time.Local)
+	if start.After(time.Now()) {
+		return c.Send("Это время еще не наступило. Введите время начала тренировки.", CancelKeyboard())
+	}
+
+	ctx := requestContext(c)
+	err = b.Dialogs.Transition(ctx, c.Sender().ID, StateAwaitingPastDuration, map[string]string{
+		dataStart: start.Format(time.RFC3339),
+	})
+	if err != nil {
+		slog.Error("save past training start err:", slog.Any("err", err))
+		return err
+	}
+
+	return c.Send("Сколько длилась тренировка? Введите минуты или часы и минуты, например 90 или 1:30.", CancelKeyboard())
+}
+
+// PastDurationHandler checks the past training, after it the user enters its sets. Nothing is stored
+// until «Готово», so a cancelled or expired entry leaves no training behind.
+func (b *BotHandler) PastDurationHandler(c telebot.Context, dialog Dialog) error {
+
+	duration, ok := parseDuration(c.Message().Text)
+	if !ok {
+		return c.Send("Не понял длительность. Введите минуты или часы и минуты, например 90 или 1:30.", CancelKeyboard())
+	}
+
+	start, err := time.Parse(time.RFC3339, dialog.Data[dataStart])
+	if err != nil {
+		slog.Error("parse past training start error:", slog.Any("err", err))
+		return err
+	}
+
+	ctx := requestContext(c)
+	training, err := b.Service.CheckPastTraining(ctx, c.Sender().ID, start, duration, time.Now())
+	switch {
+	case errors.Is(err, domain.ErrInvalidDuration):
+		return c.Send(fmt.Sprintf("Тренировка может длиться от 1 минуты до %d часов. Введите длительность.",
+			int(application.MaxTrainingDuration.Hours())), CancelKeyboard())
+	case errors.Is(err, domain.ErrTrainingInFuture):
+		return c.Send("Тренировка еще не могла закончиться. Введите длительность поменьше.", CancelKeyboard())
+	case errors.Is(err, domain.ErrTrainingOverlaps):
+		b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil)
+		return c.Send("В это время уже была другая тренировка. Выберите другой день или время.", b.currentKeyboard(c))
+	case err != nil:
+		slog.Error("check past training error:", slog.Any("err", err))
+		return err
+	}
+
+	err = b.Dialogs.Transition(ctx, c.Sender().ID, StateAwaitingPastSets, map[string]string{
+		dataDuration: duration.String(),
+	})
+	if err != nil {
+		slog.Error("save past training duration err:", slog.Any("err", err))
+		return err
+	}
+
+	return c.Send(fmt.Sprintf("Тренировка %s. Вводите сэты сообщениями, например присед 100x5x3. "+
+		"Когда закончите, нажмите «Готово», тренировка сохранится вместе с сэтами.", formatTraining(training)), PastSetsKeyboard())
+}
+
+// pastEntry is an entry of the sets of a past training kept in the dialog until the training is saved.
+// N numbers the entry for its undo button.
+type pastEntry struct {
+	N    int64
+	Sets application.PastSets
+}
+
+// encodePastEntries keeps the entries in the dialog context as "n:exercise:weight:reps:count;...".
+func encodePastEntries(entries []pastEntry) string {
+
+	var parts []string
+	for _, e := range entries {
+		parts = append(parts, strings.Join([]string{
+			strconv.FormatInt(e.N, 10),
+			strconv.FormatInt(e.Sets.Exercise_id, 10),
+			strconv.FormatFloat(e.Sets.Weight, 'f', -1, 64),
+			strconv.Itoa(e.Sets.Reps),
+			strconv.Itoa(e.Sets.Count),
+		}, ":"))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func decodePastEntries(s string) ([]pastEntry, error) {
+
+	if s == "" {
+		return nil, nil
+	}
+
+	var entries []pastEntry
+	for _, part := range strings.Split(s, ";") {
+		f := strings.Split(part, ":")
+		if len(f) != 5 {
+			return nil, fmt.Errorf("malformed past entry %q", part)
+		}
+
+		n, err1 := strconv.ParseInt(f[0], 10, 64)
+		exerciseID, err2 := strconv.ParseInt(f[1], 10, 64)
+		weight, err3 := strconv.ParseFloat(f[2], 64)
+		reps, err4 := strconv.Atoi(f[3])
+		count, err5 := strconv.Atoi(f[4])
+		if err := errors.Join(err1, err2, err3, err4, err5); err != nil {
+			return nil, fmt.Errorf("malformed past entry %q: %w", part, err)
+		}
+
+		entries = append(entries, pastEntry{N: n, Sets: application.PastSets{
+			Exercise_id: exerciseID,
+			Weight:      weight,
+			Reps:        reps,
+			Count:       count,
+		}})
+	}
+
+	return entries, nil
+}
+
+// PastSetHandler adds the sets to the past training being entered, one entry per message.
+func (b *BotHandler) PastSetHandler(c telebot.Context, dialog Dialog) error {
+
+	entries := parseQuickEntry(c.Message().Text)
+	if len(entries) == 0 {
+		return c.Send("Не понял. Введите упражнение, вес, повторения и, если нужно, число сэтов, например присед 100x5x3.",
+			PastSetsKeyboard())
+	}
+
+	return b.logEntry(c, entries, PastSetsKeyboard(), func(ctx context.Context, exerciseID int64, entry setEntry) ([]int64, error) {
+
+		sets := application.PastSets{Exercise_id: exerciseID, Weight: entry.Weight, Reps: entry.Reps, Count: entry.Sets}
+		if _, err := b.Service.CheckPastSets(ctx, c.Sender().ID, sets); err != nil {
+			return nil, err
+		}
+
+		pending, err := decodePastEntries(dialog.Data[dataPastSets])
+		if err != nil {
+			return nil, err
+		}
+
+		seq, _ := strconv.ParseInt(dialog.Data[dataPastSeq], 10, 64)
+		seq++
+		pending = append(pending, pastEntry{N: seq, Sets: sets})
+
+		// Every entry also keeps the dialog from expiring
+		err = b.Dialogs.Transition(ctx, c.Sender().ID, StateAwaitingPastSets, map[string]string{
+			dataPastSets: encodePastEntries(pending),
+			dataPastSeq:  strconv.FormatInt(seq, 10),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return []int64{seq}, nil
+	}, func(logged setRange) *telebot.ReplyMarkup {
+		return PastLogKeyboard(logged.From)
+	})
+}
+
+// UndoPastSetsHandler drops an entry from the sets of the past training being entered.
+func (b *BotHandler) UndoPastSetsHandler(c telebot.Context, n int64) error {
+
+	ctx := requestContext(c)
+	dialog, ok, err := b.pastSetsDialog(c)
+	if !ok {
+		return err
+	}
+
+	pending, err := decodePastEntries(dialog.Data[dataPastSets])
+	if err != nil {
+		slog.Error("decode past entries error:", slog.Any("err", err))
+		return err
+	}
+
+	i := slices.IndexFunc(pending, func(e pastEntry) bool { return e.N == n })
+	if i < 0 {
+		return c.Edit("Эта запись уже отменена.", PastSetsKeyboard())
+	}
+
+	err = b.Dialogs.Transition(ctx, c.Sender().ID, StateAwaitingPastSets, map[string]string{
+		dataPastSets: encodePastEntries(slices.Delete(pending, i, i+1)),
+	})
+	if err != nil {
+		slog.Error("undo past entry err:", slog.Any("err", err))
+		return err
+	}
+
+	return c.Edit("Запись отменена.", PastSetsKeyboard())
+}
+
+// PastTrainingDoneHandler stores the past training together with the entered sets. Without sets
+// nothing is stored.
+func (b *BotHandler) PastTrainingDoneHandler(c telebot.Context) error {
+
+	ctx := requestContext(c)
+	dialog, ok, err := b.pastSetsDialog(c)
+	if !ok {
+		return err
+	}
+
+	pending, err := decodePastEntries(dialog.Data[dataPastSets])
+	if err != nil {
+		slog.Error("decode past entries error:", slog.Any("err", err))
+		return err
+	}
+
+	if len(pending) == 0 {
+		b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil)
+		return c.Edit("Сэтов нет, прошедшая тренировка не сохранена.", b.currentKeyboard(c))
+	}
+
+	start, err := time.Parse(time.RFC3339, dialog.Data[dataStart])
+	if err != nil {
+		slog.Error("parse past training start error:", slog.Any("err", err))
+		return err
+	}
+
+	duration, err := time.ParseDuration(dialog.Data[dataDuration])
+	if err != nil {
+		slog.Error("parse past training duration error:", slog.Any("err", err))
+		return err
+	}
+
+	var sets []application.PastSets
+	for _, e := range pending {
+		sets = append(sets, e.Sets)
+	}
+
+	training, err := b.Service.AddPastTraining(ctx, c.Sender().ID, start, duration, sets, time.Now())
+	switch {
+	case errors.Is(err, domain.ErrUnknownExercise):
+		return c.Edit("Одно из упражнений удалено или перенесено в архив. Отмените его сэты и нажмите «Готово» снова.",
+			PastSetsKeyboard())
+	case errors.Is(err, domain.ErrTrainingOverlaps):
+		b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil)
+		return c.Edit("В это время уже появилась другая тренировка, прошедшая тренировка не сохранена.", b.currentKeyboard(c))
+	case err != nil:
+		slog.Error("add past training error:", slog.Any("err", err))
+		return err
+	}
+
+	b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil)
+
+	return c.Edit(fmt.Sprintf("Прошедшая тренировка %s сохранена.", formatTraining(training)), b.currentKeyboard(c))
+}
+
+// pastSetsDialog loads the dialog of the sets of a past training. If the sender is not entering them,
+// the button is answered as stale and ok is false.
+func (b *BotHandler) pastSetsDialog(c telebot.Context) (Dialog, bool, error) {
+
+	ctx := requestContext(c)
+	dialog, err := b.Dialogs.Current(ctx, c.Sender().ID)
+	if err != nil && !errors.Is(err, ErrDialogExpired) {
+		slog.Error("load dialog error:", slog.Any("err", err))
+		return Dialog{}, false, err
+	}
+
+	if dialog.State != StateAwaitingPastSets {
+		return Dialog{}, false, b.staleButton(c)
+	}
+
+	return dialog, true, nil
+}
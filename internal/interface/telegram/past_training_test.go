@@ -0,0 +1,83 @@
+package telegram
+
+import (
+	"GymBot/internal/application"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseClock(t *testing.T) {
+
+	tests := []struct {
+		text         string
+		hour, minute int
+		ok           bool
+	}{
+		{"18:30", 18, 30, true},
+		{"18.30", 18, 30, true},
+		{" 7 ", 7, 0, true},
+		{"07:05", 7, 5, true},
+		{"0", 0, 0, true},
+		{"23:59", 23, 59, true},
+		{"24:00", 0, 0, false},
+		{"18:60", 0, 0, false},
+		{"18:3", 0, 0, false},
+		{"18-30", 0, 0, false},
+		{"", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		hour, minute, ok := parseClock(tt.text)
+		if hour != tt.hour || minute != tt.minute || ok != tt.ok {
+			t.Errorf("parseClock(%q) = %d, %d, %t, want %d, %d, %t", tt.text, hour, minute, ok, tt.hour, tt.minute, tt.ok)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+
+	tests := []struct {
+		text string
+		want time.Duration
+		ok   bool
+	}{
+		{"90", 90 * time.Minute, true},
+		{"5", 5 * time.Minute, true},
+		{"1:30", 90 * time.Minute, true},
+		{"2.05", 2*time.Hour + 5*time.Minute, true},
+		{" 45 ", 45 * time.Minute, true},
+		{"1000", 0, false},
+		{"1:60", 0, false},
+		{"1h", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseDuration(tt.text)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseDuration(%q) = %v, %t, want %v, %t", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPastEntries(t *testing.T) {
+
+	entries := []pastEntry{
+		{N: 1, Sets: application.PastSets{Exercise_id: 3, Weight: 100, Reps: 5, Count: 3}},
+		{N: 4, Sets: application.PastSets{Exercise_id: 7, Weight: 62.5, Reps: 8, Count: 1}},
+	}
+
+	got, err := decodePastEntries(encodePastEntries(entries))
+	if err != nil || !slices.Equal(got, entries) {
+		t.Errorf("decodePastEntries(encodePastEntries(%v)) = %v, %v", entries, got, err)
+	}
+
+	if got, err := decodePastEntries(""); err != nil || got != nil {
+		t.Errorf("decodePastEntries(\"\") = %v, %v, want no entries", got, err)
+	}
+
+	if _, err := decodePastEntries("1:3:100:5"); err == nil {
+		t.Error("decodePastEntries of a malformed entry succeeded")
+	}
+}
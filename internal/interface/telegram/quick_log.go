@@ -0,0 +1,134 @@
+package telegram
+
+import (
+	"GymBot/internal/application"
+	domain "GymBot/internal/domain/entity"
+	"context"
+	"errors"
+	"fmt"
+	"gopkg.in/telebot.v3"
+	"log/slog"
+	"strings"
+)
+
+// quickEntry is a set entry typed together with the exercise name, like "присед 100x5x3".
+type quickEntry struct {
+	Name  string
+	Entry setEntry
+}
+
+// parseQuickEntry lists the ways to split the text into the exercise name and the set entry, the longest
+// name first. A name may end in a number, so "тяга 2 80x5" is either 80 kg on "тяга 2" or 2 kg on "тяга";
+// resolveQuickEntry picks the one that names an exercise.
+func parseQuickEntry(text string) []quickEntry {
+
+	var entries []quickEntry
+
+	words := strings.Fields(text)
+	for i := len(words) - 1; i >= 1; i-- {
+		if entry, ok := parseSetEntry(strings.Join(words[i:], " ")); ok {
+			entries = append(entries, quickEntry{Name: strings.Join(words[:i], " "), Entry: entry})
+		}
+	}
+
+	return entries
+}
+
+// resolveQuickEntry returns the first of the entries whose name matches the user's exercises, with the
+// matches. If no name matches, it returns the entry with the shortest name and no matches.
+func (b *BotHandler) resolveQuickEntry(ctx context.Context, id int64, entries []quickEntry) (quickEntry, []domain.Exercise, error) {
+
+	for _, e := range entries {
+		found, err := b.Service.MatchExercise(ctx, id, e.Name)
+		if err != nil {
+			return quickEntry{}, nil, err
+		}
+
+		if len(found) > 0 {
+			return e, found, nil
+		}
+	}
+
+	return entries[len(entries)-1], nil, nil
+}
+
+// exerciseNames lists the names of the ambiguous matches, as many as the search would show.
+func exerciseNames(found []domain.Exercise) string {
+
+	var names []string
+	for _, e := range found[:min(len(found), application.SearchLimit)] {
+		names = append(names, e.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// QuickLogHandler records the sets typed together with the exercise name, like "жим 80x5x3",
+// in the active training. The name may be a part of the exercise name or have a typo.
+func (b *BotHandler) QuickLogHandler(c telebot.Context, entries []quickEntry) error {
+	return b.logEntry(c, entries, b.currentKeyboard(c), func(ctx context.Context, exerciseID int64, entry setEntry) ([]int64, error) {
+		return b.Service.LogSets(ctx, c.Sender().ID, exerciseID, entry.Weight, entry.Reps, entry.Sets)
+	}, QuickLogKeyboard)
+}
+
+// logEntry finds the exercise named in the entry and records the sets with log. Unknown or ambiguous
+// names and rejected entries are answered with the keyboard, the confirmation with the done keyboard.
+func (b *BotHandler) logEntry(c telebot.Context, entries []quickEntry, keyboard *telebot.ReplyMarkup,
+	log func(ctx context.Context, exerciseID int64, entry setEntry) ([]int64, error), done func(logged setRange) *telebot.ReplyMarkup) error {
+
+	ctx := requestContext(c)
+	quick, found, err := b.resolveQuickEntry(ctx, c.Sender().ID, entries)
+	if err != nil {
+		slog.Error("match exercise error:", slog.Any("err", err))
+		return err
+	}
+
+	name, entry := quick.Name, quick.Entry
+
+	if len(found) > 1 {
+		return c.Send(fmt.Sprintf("Под '%s' подходит несколько упражнений: %s. Уточните название.", name, exerciseNames(found)), keyboard)
+	}
+
+	if len(found) == 0 {
+		return c.Send(fmt.Sprintf("Упражнение '%s' не найдено. Проверьте название или добавьте упражнение.", name), keyboard)
+	}
+
+	exercise := found[0]
+	ids, err := log(ctx, exercise.Exercise_id, entry)
+	switch {
+	case errors.Is(err, domain.ErrInvalidWeight):
+		return c.Send(invalidWeightText, keyboard)
+	case errors.Is(err, domain.ErrInvalidReps):
+		return c.Send(invalidRepsText, keyboard)
+	case errors.Is(err, domain.ErrInvalidSetCount):
+		return c.Send(fmt.Sprintf("Число сэтов должно быть от 1 до %d.", application.MaxSetsPerEntry), keyboard)
+	case errors.Is(err, domain.ErrUnknownExercise):
+		return c.Send(fmt.Sprintf("Упражнение '%s' не найдено. Проверьте название или добавьте упражнение.", name), keyboard)
+	}
+	if handled, err := replyDomainError(c, err); handled {
+		return err
+	}
+	if err != nil {
+		slog.Error("log sets error:", slog.Any("err", err))
+		return err
+	}
+
+	return c.Send(fmt.Sprintf("Записано: %s, %s.", exercise.Name, entry), done(setRange{From: ids[0], To: ids[len(ids)-1]}))
+}
+
+// UndoLogHandler removes the sets recorded by QuickLogHandler or RecordSetHandler.
+func (b *BotHandler) UndoLogHandler(c telebot.Context, logged setRange) error {
+
+	ctx := requestContext(c)
+	deleted, err := b.Service.UndoSets(ctx, c.Sender().ID, logged.From, logged.To)
+	if err != nil {
+		slog.Error("undo sets error:", slog.Any("err", err))
+		return err
+	}
+
+	if deleted == 0 {
+		return c.Edit("Эта запись уже отменена.", b.currentKeyboard(c))
+	}
+
+	return c.Edit("Запись отменена.", b.currentKeyboard(c))
+}
@@ -0,0 +1,35 @@
+package telegram
+
+import (
+	"gopkg.in/telebot.v3"
+	"log/slog"
+)
+
+func (b *BotHandler) SearchPromptHandler(c telebot.Context) error {
+
+	ctx := requestContext(c)
+	if err := b.Dialogs.Transition(ctx, c.Sender().ID, StateAwaitingSearch, nil); err != nil {
+		return c.Send("Сначала завершите текущий ввод или отмените его.", CancelKeyboard())
+	}
+
+	return c.Send("Введите название упражнения или его часть", CancelKeyboard())
+}
+
+// SearchExerciseHandler answers the search prompt. If nothing is found the prompt stays, so the user can retype.
+func (b *BotHandler) SearchExerciseHandler(c telebot.Context) error {
+
+	ctx := requestContext(c)
+	found, err := b.Service.SearchExercises(ctx, c.Sender().ID, c.Message().Text)
+	if err != nil {
+		slog.Error("search exercises error:", slog.Any("err", err))
+		return err
+	}
+
+	if len(found) == 0 {
+		return c.Send("Ничего не найдено. Попробуйте по-другому", CancelKeyboard())
+	}
+
+	b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil)
+
+	return c.Send("Найденные упражнения:", SearchResultKeyboard(found))
+}
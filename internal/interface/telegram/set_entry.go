@@ -0,0 +1,135 @@
+package telegram
+
+import (
+	"GymBot/internal/application"
+	domain "GymBot/internal/domain/entity"
+	"errors"
+	"fmt"
+	"gopkg.in/telebot.v3"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Replies to a weight or reps the service rejects.
+var (
+	invalidWeightText = fmt.Sprintf("Вес должен быть от 0 до %d кг.", application.MaxWeight)
+	invalidRepsText   = fmt.Sprintf("Количество повторений должно быть от 1 до %d.", application.MaxReps)
+)
+
+// setEntrySep splits "100x5x3", "100 х 5", "80*5" and "100 5 3" into the numbers of a set entry.
+var setEntrySep = regexp.MustCompile(`\s*[xXхХ×*]\s*|\s+`)
+
+// setEntry is a set typed in one message: the weight, the reps and how many such sets were done.
+type setEntry struct {
+	Weight float64
+	Reps   int
+	Sets   int
+}
+
+// parseSetEntry parses "<weight> <reps> [sets]", the numbers may also be joined by x, х, × or *.
+// The weight may have a comma and a leading plus, like the extra weight of a pull-up.
+func parseSetEntry(text string) (setEntry, bool) {
+
+	parts := setEntrySep.Split(strings.TrimSpace(text), -1)
+	if len(parts) < 2 || len(parts) > 3 {
+		return setEntry{}, false
+	}
+
+	weight := strings.TrimPrefix(strings.ReplaceAll(parts[0], ",", "."), "+")
+	if !weightRegexp.MatchString(weight) {
+		return setEntry{}, false
+	}
+
+	w, err := strconv.ParseFloat(weight, 64)
+	if err != nil {
+		return setEntry{}, false
+	}
+
+	entry := setEntry{Weight: w, Sets: 1}
+	for i, n := range parts[1:] {
+		if !repsRegexp.MatchString(n) {
+			return setEntry{}, false
+		}
+
+		v, err := strconv.Atoi(n)
+		if err != nil {
+			return setEntry{}, false
+		}
+
+		if i == 0 {
+			entry.Reps = v
+		} else {
+			entry.Sets = v
+		}
+	}
+
+	return entry, true
+}
+
+func (e setEntry) String() string {
+
+	set := fmt.Sprintf("%s кг × %d", formatWeight(e.Weight), e.Reps)
+	if e.Sets > 1 {
+		return fmt.Sprintf("%s, сэтов: %d", set, e.Sets)
+	}
+
+	return set
+}
+
+func formatWeight(weight float64) string {
+	return strconv.FormatFloat(weight, 'f', -1, 64)
+}
+
+func (b *BotHandler) RecordSetPromptHandler(c telebot.Context) error {
+
+	ctx := requestContext(c)
+	err := b.Service.RequireUntimedSet(ctx, c.Sender().ID)
+	if handled, err := replyDomainError(c, err); handled {
+		return err
+	}
+	if err != nil {
+		slog.Error("require untimed set error:", slog.Any("err", err))
+		return err
+	}
+
+	if err := b.Dialogs.Transition(ctx, c.Sender().ID, StateAwaitingSetEntry, nil); err != nil {
+		return c.Send("Сначала завершите текущий ввод или отмените его.", CancelKeyboard())
+	}
+
+	return c.Send("Введите вес и повторения, например 100 5. Если сделали несколько одинаковых сэтов, добавьте их число: 100 5 3.", CancelKeyboard())
+}
+
+// RecordSetHandler records the typed sets of the chosen exercise, they have no start and end time.
+func (b *BotHandler) RecordSetHandler(c telebot.Context) error {
+
+	entry, ok := parseSetEntry(c.Message().Text)
+	if !ok {
+		return c.Send("Не понял. Введите вес, повторения и, если нужно, число сэтов, например 100 5 или 100x5x3.", CancelKeyboard())
+	}
+
+	ctx := requestContext(c)
+	ids, err := b.Service.RecordSets(ctx, c.Sender().ID, entry.Weight, entry.Reps, entry.Sets)
+	switch {
+	case errors.Is(err, domain.ErrInvalidWeight):
+		return c.Send(invalidWeightText, CancelKeyboard())
+	case errors.Is(err, domain.ErrInvalidReps):
+		return c.Send(invalidRepsText, CancelKeyboard())
+	case errors.Is(err, domain.ErrInvalidSetCount):
+		return c.Send(fmt.Sprintf("Число сэтов должно быть от 1 до %d.", application.MaxSetsPerEntry), CancelKeyboard())
+	case errors.Is(err, domain.ErrExerciseNotChosen), errors.Is(err, domain.ErrSetAlreadyOpen):
+		b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil)
+	}
+	if handled, err := replyDomainError(c, err); handled {
+		return err
+	}
+	if err != nil {
+		slog.Error("record sets error:", slog.Any("err", err))
+		return err
+	}
+
+	b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil)
+
+	return c.Send(fmt.Sprintf("Записано: %s.", entry), QuickLogKeyboard(setRange{From: ids[0], To: ids[len(ids)-1]}))
+}
@@ -0,0 +1,106 @@
+package telegram
+
+import (
+	"GymBot/internal/application"
+	"GymBot/internal/infrastructure/memory"
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestParseSetEntry(t *testing.T) {
+
+	tests := []struct {
+		text  string
+		want  setEntry
+		valid bool
+	}{
+		{"100x5x3", setEntry{100, 5, 3}, true},
+		{"80 x 5", setEntry{80, 5, 1}, true},
+		{"100 х 5", setEntry{100, 5, 1}, true},
+		{"80*5", setEntry{80, 5, 1}, true},
+		{"100 5 3", setEntry{100, 5, 3}, true},
+		{"62,5x8", setEntry{62.5, 8, 1}, true},
+		{"+10x8", setEntry{10, 8, 1}, true},
+		{"0x12", setEntry{0, 12, 1}, true},
+		{"1e6x5", setEntry{}, false},
+		{"1E2 5", setEntry{}, false},
+		{"100", setEntry{}, false},
+		{"100x5x3x2", setEntry{}, false},
+		{"100x-5", setEntry{}, false},
+		{"100x5.5", setEntry{}, false},
+		{"-10x8", setEntry{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseSetEntry(tt.text)
+		if ok != tt.valid || got != tt.want {
+			t.Errorf("parseSetEntry(%q) = %v, %t, want %v, %t", tt.text, got, ok, tt.want, tt.valid)
+		}
+	}
+}
+
+func TestParseQuickEntry(t *testing.T) {
+
+	tests := []struct {
+		text string
+		want []quickEntry
+	}{
+		{"присед 100x5x3", []quickEntry{{"присед", setEntry{100, 5, 3}}}},
+		{"bench 80 x 5", []quickEntry{{"bench", setEntry{80, 5, 1}}}},
+		{"подтягивания +10x8", []quickEntry{{"подтягивания", setEntry{10, 8, 1}}}},
+		{"жим лежа 62,5 8", []quickEntry{{"жим лежа", setEntry{62.5, 8, 1}}}},
+		{"тяга 2 80x5", []quickEntry{{"тяга 2", setEntry{80, 5, 1}}, {"тяга", setEntry{2, 80, 5}}}},
+		{"присед 100 5 3", []quickEntry{{"присед 100", setEntry{5, 3, 1}}, {"присед", setEntry{100, 5, 3}}}},
+		{"жим 1e6x5", nil},
+		{"жим", nil},
+		{"100x5", nil},
+	}
+
+	for _, tt := range tests {
+		if got := parseQuickEntry(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("parseQuickEntry(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestResolveQuickEntry(t *testing.T) {
+
+	ctx := context.Background()
+	st := memory.NewStorage()
+	b := &BotHandler{Service: application.Initialize(st, st, st, st, st, st)}
+
+	for _, name := range []string{"Присед", "Тяга", "Тяга 2"} {
+		if _, err := b.Service.AddExercise(ctx, 1, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		text  string
+		want  quickEntry
+		found string
+	}{
+		{"тяга 2 80x5", quickEntry{"тяга 2", setEntry{80, 5, 1}}, "Тяга 2"},
+		{"тяга 80x5", quickEntry{"тяга", setEntry{80, 5, 1}}, "Тяга"},
+		{"присед 100 5 3", quickEntry{"присед", setEntry{100, 5, 3}}, "Присед"},
+		{"пресед 100x5", quickEntry{"пресед", setEntry{100, 5, 1}}, "Присед"},
+		{"жим 2 80x5", quickEntry{"жим", setEntry{2, 80, 5}}, ""},
+	}
+
+	for _, tt := range tests {
+		got, found, err := b.resolveQuickEntry(ctx, 1, parseQuickEntry(tt.text))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var name string
+		if len(found) == 1 {
+			name = found[0].Name
+		}
+
+		if got != tt.want || name != tt.found || len(found) > 1 {
+			t.Errorf("resolveQuickEntry(%q) = %v, %v, want %v, %q", tt.text, got, found, tt.want, tt.found)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package telegram
+
+import (
+	domain "GymBot/internal/domain/entity"
+	"errors"
+	"fmt"
+	"gopkg.in/telebot.v3"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// formatSet is "Присед, 100 кг × 5".
+func formatSet(set domain.Set) string {
+	return fmt.Sprintf("%s, %s кг × %d", set.Exercise, formatWeight(set.Weight), set.Reps)
+}
+
+// UndoSetHandler removes the set recorded by RepsHandler, whichever sets were recorded after it.
+func (b *BotHandler) UndoSetHandler(c telebot.Context, setID int64) error {
+
+	ctx := requestContext(c)
+	set, err := b.Service.Set(ctx, c.Sender().ID, setID)
+	if err == nil {
+		err = b.Service.DeleteSet(ctx, c.Sender().ID, setID)
+	}
+	if errors.Is(err, domain.ErrUnknownSet) {
+		return c.Edit("Этот сэт уже отменен.", b.currentKeyboard(c))
+	}
+	if err != nil {
+		slog.Error("undo set error:", slog.Any("err", err))
+		return err
+	}
+
+	return c.Edit(fmt.Sprintf("Сэт отменен: %s.", formatSet(set)), b.currentKeyboard(c))
+}
+
+func (b *BotHandler) EditSetsHandler(c telebot.Context) error {
+	return b.editSets(c, "Какой сэт изменить?")
+}
+
+// editSets lists the recorded sets of the active training.
+func (b *BotHandler) editSets(c telebot.Context, text string) error {
+
+	ctx := requestContext(c)
+	sets, err := b.Service.TrainingSets(ctx, c.Sender().ID)
+	if handled, err := replyDomainError(c, err); handled {
+		return err
+	}
+	if err != nil {
+		slog.Error("training sets error:", slog.Any("err", err))
+		return err
+	}
+
+	if len(sets) == 0 {
+		return c.Edit("В этой тренировке пока нет записанных сэтов.", TrainingKeyboard())
+	}
+
+	return c.Edit(text, SetsKeyboard(sets))
+}
+
+func (b *BotHandler) SetMenuHandler(c telebot.Context, setID int64) error {
+
+	set, ok, err := b.set(c, setID)
+	if !ok {
+		return err
+	}
+
+	return c.Edit(fmt.Sprintf("Сэт: %s", formatSet(set)), SetMenuKeyboard(set))
+}
+
+func (b *BotHandler) EditSetWeightPromptHandler(c telebot.Context, setID int64) error {
+	return b.editSetPrompt(c, setID, StateAwaitingSetWeight, "Сэт: %s. Введите новый вес.")
+}
+
+func (b *BotHandler) EditSetRepsPromptHandler(c telebot.Context, setID int64) error {
+	return b.editSetPrompt(c, setID, StateAwaitingSetReps, "Сэт: %s. Введите новое количество повторений.")
+}
+
+func (b *BotHandler) EditSetExercisePromptHandler(c telebot.Context, setID int64) error {
+	return b.editSetPrompt(c, setID, StateAwaitingSetExercise, "Сэт: %s. Введите упражнение, к которому он относится.")
+}
+
+func (b *BotHandler) editSetPrompt(c telebot.Context, setID int64, state DialogState, prompt string) error {
+
+	set, ok, err := b.set(c, setID)
+	if !ok {
+		return err
+	}
+
+	ctx := requestContext(c)
+	err = b.Dialogs.Transition(ctx, c.Sender().ID, state, map[string]string{
+		dataSet: strconv.FormatInt(set.Set_id, 10),
+	})
+	if err != nil {
+		return c.Send("Сначала завершите текущий ввод или отмените его.", CancelKeyboard())
+	}
+
+	return c.Send(fmt.Sprintf(prompt, formatSet(set)), CancelKeyboard())
+}
+
+func (b *BotHandler) SetWeightHandler(c telebot.Context, dialog Dialog) error {
+
+	msg := strings.ReplaceAll(c.Message().Text, ",", ".")
+	if !weightRegexp.MatchString(msg) {
+		return c.Send("Ошибка ввода веса. Введите число, например 62.5", CancelKeyboard())
+	}
+
+	weight, err := strconv.ParseFloat(msg, 64)
+	if err != nil {
+		return c.Send("Ошибка ввода веса. Введите число, например 62.5", CancelKeyboard())
+	}
+
+	ctx := requestContext(c)
+	set, err := b.Service.EditSetWeight(ctx, c.Sender().ID, parseDialogID(dialog.Data[dataSet]), weight)
+	if errors.Is(err, domain.ErrInvalidWeight) {
+		return c.Send(invalidWeightText, CancelKeyboard())
+	}
+
+	return b.replyEditedSet(c, set, err)
+}
+
+func (b *BotHandler) SetRepsHandler(c telebot.Context, dialog Dialog) error {
+
+	if !repsRegexp.MatchString(c.Message().Text) {
+		return c.Send("Ошибка ввода повторений. Пожалуйста, введите целое число.", CancelKeyboard())
+	}
+
+	reps, err := strconv.Atoi(c.Message().Text)
+	if err != nil {
+		return c.Send("Ошибка ввода повторений. Пожалуйста, введите целое число.", CancelKeyboard())
+	}
+
+	ctx := requestContext(c)
+	set, err := b.Service.EditSetReps(ctx, c.Sender().ID, parseDialogID(dialog.Data[dataSet]), reps)
+	if errors.Is(err, domain.ErrInvalidReps) {
+		return c.Send(invalidRepsText, CancelKeyboard())
+	}
+
+	return b.replyEditedSet(c, set, err)
+}
+
+// SetExerciseHandler moves the set to the typed exercise, the name is matched like in the search.
+func (b *BotHandler) SetExerciseHandler(c telebot.Context, dialog Dialog) error {
+
+	ctx := requestContext(c)
+	found, err := b.Service.MatchExercise(ctx, c.Sender().ID, c.Message().Text)
+	if err != nil {
+		slog.Error("match exercise error:", slog.Any("err", err))
+		return err
+	}
+
+	if len(found) > 1 {
+		return c.Send(fmt.Sprintf("Подходит несколько упражнений: %s. Уточните название.", exerciseNames(found)), CancelKeyboard())
+	}
+
+	if len(found) == 0 {
+		return c.Send("Упражнение не найдено. Введите другое название.", CancelKeyboard())
+	}
+
+	set, err := b.Service.EditSetExercise(ctx, c.Sender().ID, parseDialogID(dialog.Data[dataSet]), found[0].Exercise_id)
+	if errors.Is(err, domain.ErrUnknownExercise) {
+		return c.Send("Упражнение не найдено. Введите другое название.", CancelKeyboard())
+	}
+
+	return b.replyEditedSet(c, set, err)
+}
+
+// replyEditedSet ends the edit dialog and shows the changed set.
+func (b *BotHandler) replyEditedSet(c telebot.Context, set domain.Set, err error) error {
+
+	ctx := requestContext(c)
+	if errors.Is(err, domain.ErrUnknownSet) {
+		b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil)
+		return c.Send("Этого сэта больше нет.", b.currentKeyboard(c))
+	}
+	if err != nil {
+		slog.Error("edit set error:", slog.Any("err", err))
+		return err
+	}
+
+	b.Dialogs.Transition(ctx, c.Sender().ID, StateIdle, nil)
+
+	return c.Send(fmt.Sprintf("Сэт изменен: %s.", formatSet(set)), SetMenuKeyboard(set))
+}
+
+func (b *BotHandler) DeleteSetHandler(c telebot.Context, setID int64) error {
+
+	set, ok, err := b.set(c, setID)
+	if !ok {
+		return err
+	}
+
+	ctx := requestContext(c)
+	err = b.Service.DeleteSet(ctx, c.Sender().ID, setID)
+	if errors.Is(err, domain.ErrUnknownSet) {
+		return b.editSets(c, "Этого сэта больше нет. Какой сэт изменить?")
+	}
+	if err != nil {
+		slog.Error("delete set error:", slog.Any("err", err))
+		return err
+	}
+
+	return c.Edit(fmt.Sprintf("Сэт удален: %s.", formatSet(set)), TrainingKeyboard())
+}
+
+// set loads the sender's recorded set. If it is not theirs or no longer exists, the sets of the training
+// are listed instead and ok is false.
+func (b *BotHandler) set(c telebot.Context, setID int64) (domain.Set, bool, error) {
+
+	ctx := requestContext(c)
+	set, err := b.Service.Set(ctx, c.Sender().ID, setID)
+	if errors.Is(err, domain.ErrUnknownSet) {
+		return domain.Set{}, false, b.editSets(c, "Этого сэта больше нет. Какой сэт изменить?")
+	}
+	if err != nil {
+		slog.Error("get set error:", slog.Any("err", err))
+		return domain.Set{}, false, err
+	}
+
+	return set, true, nil
+}